@@ -1,6 +1,9 @@
 package goease
 
-import "unicode"
+import (
+	"strings"
+	"unicode"
+)
 
 // ConvertPascalToSnakeWithExtraKey converts keys in a map from PascalCase to snake_case.
 // It also checks for additional key mappings defined in configs.KEY_CONVERT_MAPPING
@@ -31,6 +34,14 @@ func ConvertPascalToSnakeWithExtraKey(input map[string]interface{}, extraKeyMapp
 }
 
 // convertPascalToSnakeCase converts a string from PascalCase to snake_case.
+// Runs of uppercase letters are treated as a single word (acronyms), and digits
+// stay attached to the word they follow.
+//
+// Examples:
+//   UserID -> user_id, HTTPServer -> http_server, Base64Encoder -> base64_encoder
+//
+// Mixed-case words listed in mixedCaseWords are kept together, so OAuth2Token
+// becomes oauth2_token rather than o_auth2_token.
 //
 // Parameters:
 //   s: A string in PascalCase.
@@ -38,12 +49,176 @@ func ConvertPascalToSnakeWithExtraKey(input map[string]interface{}, extraKeyMapp
 // Returns:
 //   A string converted to snake_case.
 func convertPascalToSnakeCase(s string) string {
+	runes := []rune(s)
 	var result []rune
-	for i, r := range s {
-		if i > 0 && unicode.IsUpper(r) {
+	for i, r := range runes {
+		if isCaseWordStart(runes, i) {
 			result = append(result, '_')
 		}
 		result = append(result, unicode.ToLower(r))
 	}
 	return string(result)
 }
+
+// isCaseWordStart reports whether runes[i] starts a new word of a PascalCase or
+// camelCase string: an uppercase letter following a lowercase letter or a digit,
+// or the last uppercase letter of an acronym when a lowercase letter follows it.
+func isCaseWordStart(runes []rune, i int) bool {
+	if i == 0 || !unicode.IsUpper(runes[i]) || insideMixedCaseWord(runes, i) {
+		return false
+	}
+	prev := runes[i-1]
+	nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+	return unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower)
+}
+
+// mixedCaseWords lists the words whose inner uppercase letters do not start a
+// new word, e.g. "OAuth" which would otherwise be split into "O" and "Auth".
+var mixedCaseWords = []string{"OAuth"}
+
+// insideMixedCaseWord reports whether runes[i] is part of one of mixedCaseWords,
+// other than its first letter, starting at the beginning of a word.
+func insideMixedCaseWord(runes []rune, i int) bool {
+	for _, word := range mixedCaseWords {
+		w := []rune(word)
+		for start := max(0, i-len(w)+1); start < i; start++ {
+			end := start + len(w)
+			if end <= len(runes) && string(runes[start:end]) == word && (start == 0 || !unicode.IsUpper(runes[start-1])) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ConvertPascalToSnakeDeep converts keys in a map from PascalCase to snake_case
+// at every level. It behaves like ConvertPascalToSnakeWithExtraKey, but also
+// recurses into nested maps (including JSONB) and into maps held in slices.
+//
+// Parameters:
+//   input: A map[string]interface{} representing the input data with keys possibly in PascalCase.
+//   extraKeyMappings: Key mappings taking precedence over the snake_case conversion, applied at every level.
+//
+// Returns:
+//   A new map[string]interface{} with keys converted to snake_case at every level.
+//   The input map is left untouched.
+func ConvertPascalToSnakeDeep(input map[string]interface{}, extraKeyMappings map[string]string) map[string]interface{} {
+	return TransformMapKeys(input, func(key string) string {
+		if mappedKey, ok := extraKeyMappings[key]; ok {
+			return mappedKey
+		}
+		return convertPascalToSnakeCase(key)
+	})
+}
+
+// ConvertToCamelCase converts keys in a map from snake_case or PascalCase to
+// camelCase at every level, e.g. "user_name" and "UserName" both become "userName".
+//
+// Parameters:
+//   input: A map[string]interface{} representing the input data.
+//
+// Returns:
+//   A new map[string]interface{} with keys converted to camelCase at every level.
+func ConvertToCamelCase(input map[string]interface{}) map[string]interface{} {
+	return TransformMapKeys(input, convertToCamelCase)
+}
+
+// NormalizeKeys converts the keys of every element of a JSONBA to the given
+// style at every level, e.g. before inserting rows decoded from an API.
+//
+// Parameters:
+//   style: Either "snake" for snake_case or "camel" for camelCase. Any other
+//   style leaves the keys as they are.
+//
+// Returns:
+//   A new JSONBA with converted keys. The input JSONBA is left untouched.
+func (a JSONBA) NormalizeKeys(style string) JSONBA {
+	fn := func(key string) string { return key }
+	switch style {
+	case "snake":
+		fn = convertPascalToSnakeCase
+	case "camel":
+		fn = convertToCamelCase
+	}
+
+	normalized := make(JSONBA, len(a))
+	for i, item := range a {
+		normalized[i] = TransformMapKeys(item, fn)
+	}
+	return normalized
+}
+
+// TransformMapKeys applies fn to every key of a map, recursing into nested maps
+// (including JSONB) and into maps held in slices. It can be used to build any
+// key naming convention.
+//
+// Parameters:
+//   input: A map[string]interface{} representing the input data.
+//   fn: The function returning the new name of a key.
+//
+// Returns:
+//   A new map[string]interface{} with transformed keys at every level.
+//   The input map is left untouched.
+func TransformMapKeys(input map[string]interface{}, fn func(string) string) map[string]interface{} {
+	converted := make(map[string]interface{}, len(input))
+	for key, value := range input {
+		converted[fn(key)] = transformMapKeysValue(value, fn)
+	}
+
+	return converted
+}
+
+// transformMapKeysValue transforms the keys of the maps found in value, recursing into slices.
+func transformMapKeysValue(value interface{}, fn func(string) string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return TransformMapKeys(v, fn)
+	case JSONB:
+		return JSONB(TransformMapKeys(v, fn))
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = transformMapKeysValue(item, fn)
+		}
+		return items
+	case []map[string]interface{}:
+		items := make([]map[string]interface{}, len(v))
+		for i, item := range v {
+			items[i] = TransformMapKeys(item, fn)
+		}
+		return items
+	case JSONBA:
+		items := make(JSONBA, len(v))
+		for i, item := range v {
+			items[i] = TransformMapKeys(item, fn)
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+// convertToCamelCase converts a string from snake_case or PascalCase to camelCase.
+// The string is split into words on underscores and with the same acronym rules
+// as convertPascalToSnakeCase, so "user_id" and "UserID" both become "userId",
+// and "HTTPServer" becomes "httpServer".
+//
+// Parameters:
+//   s: A string in snake_case or PascalCase.
+//
+// Returns:
+//   A string converted to camelCase.
+func convertToCamelCase(s string) string {
+	var result []rune
+	for _, part := range strings.Split(s, "_") {
+		runes := []rune(part)
+		for i, r := range runes {
+			if len(result) > 0 && (i == 0 || isCaseWordStart(runes, i)) {
+				result = append(result, unicode.ToUpper(r))
+			} else {
+				result = append(result, unicode.ToLower(r))
+			}
+		}
+	}
+	return string(result)
+}
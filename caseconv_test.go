@@ -0,0 +1,151 @@
+package goease
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConvertPascalToSnakeDeep(t *testing.T) {
+	input := map[string]interface{}{
+		"UserName": "john",
+		"UserID":   1,
+		"Address": map[string]interface{}{
+			"StreetName": "Main",
+			"ZipCode":    "10110",
+		},
+		"Orders": []interface{}{
+			map[string]interface{}{"OrderID": 1, "TotalPrice": 9.99},
+			map[string]interface{}{"OrderID": 2, "TotalPrice": 19.5},
+		},
+	}
+	extra := map[string]string{"UserID": "user_id", "OrderID": "order_id"}
+
+	expected := map[string]interface{}{
+		"user_name": "john",
+		"user_id":   1,
+		"address": map[string]interface{}{
+			"street_name": "Main",
+			"zip_code":    "10110",
+		},
+		"orders": []interface{}{
+			map[string]interface{}{"order_id": 1, "total_price": 9.99},
+			map[string]interface{}{"order_id": 2, "total_price": 19.5},
+		},
+	}
+
+	got := ConvertPascalToSnakeDeep(input, extra)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v got %v", expected, got)
+	}
+
+	if _, ok := input["Address"].(map[string]interface{})["StreetName"]; !ok {
+		t.Error("expected input to be left untouched")
+	}
+}
+
+func TestConvertToCamelCase(t *testing.T) {
+	input := map[string]interface{}{
+		"user_name": "john",
+		"UserID":    1,
+		"home_address": map[string]interface{}{
+			"street_name": "Main",
+		},
+		"orders": []interface{}{
+			map[string]interface{}{"order_id": 1},
+		},
+	}
+
+	expected := map[string]interface{}{
+		"userName": "john",
+		"userId":   1,
+		"homeAddress": map[string]interface{}{
+			"streetName": "Main",
+		},
+		"orders": []interface{}{
+			map[string]interface{}{"orderId": 1},
+		},
+	}
+
+	if got := ConvertToCamelCase(input); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v got %v", expected, got)
+	}
+}
+
+func TestConvertToCamelCaseString(t *testing.T) {
+	tests := map[string]string{
+		"user_name":      "userName",
+		"UserName":       "userName",
+		"user_id":        "userId",
+		"UserID":         "userId",
+		"HTTPServer":     "httpServer",
+		"OAuth2Token":    "oauth2Token",
+		"http_server":    "httpServer",
+		"Base64Encoder":  "base64Encoder",
+		"_leading_under": "leadingUnder",
+		"name":           "name",
+	}
+	for input, expected := range tests {
+		if got := convertToCamelCase(input); got != expected {
+			t.Errorf("%s: expected %q got %q", input, expected, got)
+		}
+	}
+}
+
+func TestTransformMapKeys(t *testing.T) {
+	input := map[string]interface{}{
+		"name": "john",
+		"tags": []map[string]interface{}{{"label": "admin"}},
+	}
+
+	expected := map[string]interface{}{
+		"NAME": "john",
+		"TAGS": []map[string]interface{}{{"LABEL": "admin"}},
+	}
+
+	if got := TransformMapKeys(input, strings.ToUpper); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v got %v", expected, got)
+	}
+}
+
+func TestConvertPascalToSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"UserName":      "user_name",
+		"UserID":        "user_id",
+		"HTTPServer":    "http_server",
+		"APIKey":        "api_key",
+		"OAuth2Token":   "oauth2_token",
+		"UserOAuthID":   "user_oauth_id",
+		"Base64Encoder": "base64_encoder",
+		"ID":            "id",
+		"name":          "name",
+	}
+	for input, expected := range tests {
+		if got := convertPascalToSnakeCase(input); got != expected {
+			t.Errorf("%s: expected %q got %q", input, expected, got)
+		}
+	}
+}
+
+func TestJSONBANormalizeKeys(t *testing.T) {
+	rows := JSONBA{
+		{"UserID": 1, "FirstName": "John", "Address": map[string]interface{}{"ZipCode": "10110"}},
+		{"UserID": 2, "FirstName": "Jane"},
+	}
+
+	expected := JSONBA{
+		{"user_id": 1, "first_name": "John", "address": map[string]interface{}{"zip_code": "10110"}},
+		{"user_id": 2, "first_name": "Jane"},
+	}
+	if got := rows.NormalizeKeys("snake"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v got %v", expected, got)
+	}
+	if _, ok := rows[0]["UserID"]; !ok {
+		t.Error("expected input to be left untouched")
+	}
+
+	camel := JSONBA{{"user_id": 1, "HTTPServer": "a"}}.NormalizeKeys("camel")
+	if !reflect.DeepEqual(camel, JSONBA{{"userId": 1, "httpServer": "a"}}) {
+		t.Errorf("unexpected camelCase result %v", camel)
+	}
+}
@@ -1,6 +1,9 @@
 package goease
 
 import (
+	"fmt"
+	"net/mail"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -12,12 +15,7 @@ import (
 // intContains := IntContains(intSlice, 20)
 // fmt.Println("Slice contains 20:", intContains)
 func IntContains(slice []int, element int) bool {
-	for _, item := range slice {
-		if item == element {
-			return true
-		}
-	}
-	return false
+	return Contains(slice, element)
 }
 
 // Trim String Spaces
@@ -28,6 +26,53 @@ func TrimSpaces(str string) string {
 	return strings.TrimSpace(str)
 }
 
+// Check if String is empty or only contains whitespace
+// Example usage:
+// blank := IsBlank("   ")
+// fmt.Println("String is blank:", blank)
+func IsBlank(s string) bool {
+	return TrimSpaces(s) == ""
+}
+
+// Return a Default value if String is blank
+// Example usage:
+// name := DefaultIfBlank("  ", "anonymous")
+// fmt.Println("Name:", name)
+func DefaultIfBlank(s, def string) string {
+	if IsBlank(s) {
+		return def
+	}
+	return s
+}
+
+// Return the first value that is not the zero value of its type, or the zero value if there is none
+// Example usage:
+// port := Coalesce(flagPort, configPort, 8080)
+// fmt.Println("Port:", port)
+func Coalesce[T comparable](values ...T) T {
+	var zero T
+	for _, value := range values {
+		if value != zero {
+			return value
+		}
+	}
+	return zero
+}
+
+// Return the first String that is not blank, or an empty String if there is none
+// Unlike Coalesce, Strings only made of whitespace are skipped too
+// Example usage:
+// dsn := FirstNonEmptyString(os.Getenv("DATABASE_URL"), cfg.DSN, "postgres://localhost/app")
+// fmt.Println("DSN:", dsn)
+func FirstNonEmptyString(values ...string) string {
+	for _, value := range values {
+		if !IsBlank(value) {
+			return value
+		}
+	}
+	return ""
+}
+
 // Convert String to Boolean
 // Example usage:
 // b, err := StringToBool("true")
@@ -42,6 +87,28 @@ func StringToBool(str string) (bool, error) {
 	return strconv.ParseBool(str)
 }
 
+// Convert human friendly String to Boolean
+// Accepts "true"/"false", "yes"/"no", "on"/"off" and "1"/"0", case-insensitive and ignoring surrounding whitespace
+// Example usage:
+// b, err := StringToBoolLenient(" Yes ")
+//
+//	if err != nil {
+//	    fmt.Println("Error converting string to bool:", err)
+//	} else {
+//
+//	    fmt.Println("Converted boolean:", b)
+//	}
+func StringToBoolLenient(str string) (bool, error) {
+	switch strings.ToLower(TrimSpaces(str)) {
+	case "true", "yes", "on", "1":
+		return true, nil
+	case "false", "no", "off", "0":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid boolean value: %q", str)
+	}
+}
+
 // Format Unix Time to String
 // Example usage:
 // formattedTime := FormatUnixTime(1609459200, "2006-01-02 15:04:05")
@@ -49,3 +116,58 @@ func StringToBool(str string) (bool, error) {
 func FormatUnixTime(unixTime int64, layout string) string {
 	return time.Unix(unixTime, 0).Format(layout)
 }
+
+// Check if String is a valid Email Address
+// Display names such as "John <john@example.com>" are not accepted
+// Example usage:
+// valid := IsValidEmail("john+news@example.com")
+// fmt.Println("Valid email:", valid)
+func IsValidEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return false
+	}
+
+	at := strings.LastIndex(email, "@")
+	return at > 0 && at < len(email)-1
+}
+
+// Check if String is a valid absolute http or https URL
+// Example usage:
+// valid := IsValidURL("https://example.com/path")
+// fmt.Println("Valid URL:", valid)
+func IsValidURL(rawurl string) bool {
+	u, err := url.ParseRequestURI(rawurl)
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// Format Unix Time in Milliseconds to String
+// Sub-second precision is kept, so it can be displayed with a layout such as "15:04:05.000"
+// Example usage:
+// formattedTime := FormatUnixMillis(1609459200123, "2006-01-02 15:04:05.000")
+// fmt.Println("Formatted time:", formattedTime)
+func FormatUnixMillis(ms int64, layout string) string {
+	return time.UnixMilli(ms).Format(layout)
+}
+
+// Format Unix Time to String in the given IANA Time Zone
+// Example usage:
+// formattedTime, err := FormatUnixTimeInLocation(1609459200, "2006-01-02 15:04:05", "Asia/Bangkok")
+//
+//	if err != nil {
+//	    fmt.Println("Error formatting time:", err)
+//	} else {
+//
+//	    fmt.Println("Formatted time:", formattedTime)
+//	}
+func FormatUnixTimeInLocation(unixTime int64, layout, tzName string) (string, error) {
+	t, err := ConvertToTimezone(time.Unix(unixTime, 0), tzName)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(layout), nil
+}
@@ -0,0 +1,158 @@
+package goease
+
+import "testing"
+
+func TestIsValidEmail(t *testing.T) {
+	valid := []string{
+		"john@example.com",
+		"john.doe@mail.example.co.th",
+		"john+news@example.com",
+	}
+	for _, email := range valid {
+		if !IsValidEmail(email) {
+			t.Errorf("expected %q to be valid", email)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"john",
+		"john@",
+		"@example.com",
+		"john doe@example.com",
+		"John <john@example.com>",
+	}
+	for _, email := range invalid {
+		if IsValidEmail(email) {
+			t.Errorf("expected %q to be invalid", email)
+		}
+	}
+}
+
+func TestIsValidURL(t *testing.T) {
+	valid := []string{
+		"https://example.com",
+		"http://example.com:8080/path?q=1",
+	}
+	for _, rawurl := range valid {
+		if !IsValidURL(rawurl) {
+			t.Errorf("expected %q to be valid", rawurl)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"ftp://host",
+		"/relative",
+		"javascript:alert(1)",
+		"https://",
+		"http://[::1",
+	}
+	for _, rawurl := range invalid {
+		if IsValidURL(rawurl) {
+			t.Errorf("expected %q to be invalid", rawurl)
+		}
+	}
+}
+
+func TestIsBlank(t *testing.T) {
+	for _, s := range []string{"", " ", "\t\n  "} {
+		if !IsBlank(s) {
+			t.Errorf("expected %q to be blank", s)
+		}
+		if got := DefaultIfBlank(s, "default"); got != "default" {
+			t.Errorf("expected default value for %q got %q", s, got)
+		}
+	}
+
+	for _, s := range []string{"a", "  hello  "} {
+		if IsBlank(s) {
+			t.Errorf("expected %q to not be blank", s)
+		}
+		if got := DefaultIfBlank(s, "default"); got != s {
+			t.Errorf("expected %q to be returned untouched got %q", s, got)
+		}
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	if got := Coalesce(8080, 0, 3000); got != 8080 {
+		t.Errorf("expected first value got %d", got)
+	}
+	if got := Coalesce("", "config", "default"); got != "config" {
+		t.Errorf("expected middle value got %q", got)
+	}
+	if got := Coalesce(0, 0); got != 0 {
+		t.Errorf("expected zero value got %d", got)
+	}
+	if got := Coalesce[int](); got != 0 {
+		t.Errorf("expected zero value without values got %d", got)
+	}
+}
+
+func TestFirstNonEmptyString(t *testing.T) {
+	if got := FirstNonEmptyString("env", "config"); got != "env" {
+		t.Errorf("expected first value got %q", got)
+	}
+	if got := FirstNonEmptyString("", "  ", "config", "default"); got != "config" {
+		t.Errorf("expected middle value got %q", got)
+	}
+	if got := FirstNonEmptyString("", " \t"); got != "" {
+		t.Errorf("expected empty string got %q", got)
+	}
+}
+
+func TestStringToBoolLenient(t *testing.T) {
+	tests := map[string]bool{
+		"true": true, "TRUE": true, "yes": true, " Yes ": true, "on": true, "ON": true, "1": true,
+		"false": false, "False": false, "no": false, "NO": false, "off": false, "Off": false, "0": false,
+	}
+	for input, expected := range tests {
+		got, err := StringToBoolLenient(input)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", input, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("%q: expected %v got %v", input, expected, got)
+		}
+	}
+
+	for _, input := range []string{"", "maybe", "2", "y"} {
+		if _, err := StringToBoolLenient(input); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestFormatUnixMillis(t *testing.T) {
+	const layout = "2006-01-02 15:04:05"
+
+	if FormatUnixMillis(1609459200000, layout) != FormatUnixTime(1609459200, layout) {
+		t.Error("expected seconds and milliseconds formatting of the same instant to match")
+	}
+
+	formatted, err := FormatUnixTimeInLocation(1609459200, layout+".000", "UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if formatted != "2021-01-01 00:00:00.000" {
+		t.Errorf("unexpected formatted time %q", formatted)
+	}
+
+	if got := FormatUnixMillis(1609459200123, ".000"); got != ".123" {
+		t.Errorf("expected sub-second precision to be kept got %q", got)
+	}
+
+	formatted, err = FormatUnixTimeInLocation(1609459200, layout, "Asia/Bangkok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if formatted != "2021-01-01 07:00:00" {
+		t.Errorf("unexpected formatted time %q", formatted)
+	}
+
+	if _, err := FormatUnixTimeInLocation(1609459200, layout, "Mars/Olympus_Mons"); err == nil {
+		t.Error("expected error for unknown time zone")
+	}
+}
@@ -46,6 +46,34 @@ func StringToInt(str string) (int, error) {
 	return strconv.Atoi(str)
 }
 
+// String to Int64 Conversion
+// Example usage:
+// id, err := StringToInt64("1541815603606036480")
+//
+//	if err != nil {
+//	    fmt.Println("Error converting string to int64:", err)
+//	} else {
+//
+//	    fmt.Println("Converted number:", id)
+//	}
+func StringToInt64(str string) (int64, error) {
+	return strconv.ParseInt(str, 10, 64)
+}
+
+// String to Uint64 Conversion
+// Example usage:
+// id, err := StringToUint64("18446744073709551615")
+//
+//	if err != nil {
+//	    fmt.Println("Error converting string to uint64:", err)
+//	} else {
+//
+//	    fmt.Println("Converted number:", id)
+//	}
+func StringToUint64(str string) (uint64, error) {
+	return strconv.ParseUint(str, 10, 64)
+}
+
 // String to Float Conversion
 // Example usage:
 // f, err := StringToFloat("123.45")
@@ -60,6 +88,33 @@ func StringToFloat(str string) (float64, error) {
 	return strconv.ParseFloat(str, 64)
 }
 
+// String to Int Conversion with a Default value
+// The default value is returned when the string is empty or is not a valid integer, surrounding whitespace being ignored
+// Example usage:
+// port := ParseIntDefault(os.Getenv("PORT"), 8080)
+// fmt.Println("Port:", port)
+func ParseIntDefault(str string, def int) int {
+	num, err := strconv.Atoi(strings.TrimSpace(str))
+	if err != nil {
+		return def
+	}
+	return num
+}
+
+// String to Boolean Conversion with a Default value
+// The string is parsed with StringToBoolLenient, so "yes", "on" or "1" are accepted as well,
+// and the default value is returned when the string is empty or is not a valid boolean
+// Example usage:
+// debug := ParseBoolDefault(os.Getenv("DEBUG"), false)
+// fmt.Println("Debug:", debug)
+func ParseBoolDefault(str string, def bool) bool {
+	b, err := StringToBoolLenient(str)
+	if err != nil {
+		return def
+	}
+	return b
+}
+
 // Int to String Conversion
 // Example usage:
 // str := IntToString(123)
@@ -75,8 +130,17 @@ func IntToString(num int) string {
 // contains := StringContains(slice, "banana")
 // fmt.Println("Slice contains 'banana':", contains)
 func StringContains(s []string, e string) bool {
-	for _, a := range s {
-		if a == e {
+	return Contains(s, e)
+}
+
+// Check if an element is in a Slice of any comparable type
+// Example usage:
+// slice := []string{"apple", "banana", "cherry"}
+// contains := Contains(slice, "banana")
+// fmt.Println("Slice contains 'banana':", contains)
+func Contains[T comparable](slice []T, element T) bool {
+	for _, item := range slice {
+		if item == element {
 			return true
 		}
 	}
@@ -89,11 +153,16 @@ func StringContains(s []string, e string) bool {
 // joined := JoinInts(ints, ", ")
 // fmt.Println("Joined string:", joined)
 func JoinInts(ints []int, sep string) string {
-	var strSlice []string
-	for _, num := range ints {
-		strSlice = append(strSlice, strconv.Itoa(num))
-	}
-	return strings.Join(strSlice, sep)
+	return JoinNumbers(ints, sep)
+}
+
+// Join Number Slice of any integer or float type to String
+// Example usage:
+// prices := []float64{9.99, 19.5, 100}
+// joined := JoinNumbers(prices, ", ")
+// fmt.Println("Joined string:", joined) // 9.99, 19.5, 100
+func JoinNumbers[T Number](nums []T, sep string) string {
+	return strings.Join(Map(nums, NumberToString[T]), sep)
 }
 
 // Float to String Conversion
@@ -102,4 +171,70 @@ func JoinInts(ints []int, sep string) string {
 // fmt.Println("Converted string:", floatStr)
 func FloatToString(num float64) string {
 	return strconv.FormatFloat(num, 'f', -1, 64)
-}
\ No newline at end of file
+}
+
+// Remove duplicates from a Slice, keeping the first occurrence order
+// A nil slice returns nil
+// Example usage:
+// tags := []string{"go", "db", "go", "api", "db"}
+// unique := Unique(tags)
+// fmt.Println("Unique tags:", unique) // [go db api]
+func Unique[T comparable](s []T) []T {
+	if s == nil {
+		return nil
+	}
+
+	seen := make(map[T]struct{}, len(s))
+	result := make([]T, 0, len(s))
+	for _, item := range s {
+		if _, ok := seen[item]; ok {
+			continue
+		}
+		seen[item] = struct{}{}
+		result = append(result, item)
+	}
+	return result
+}
+
+// Integer is a constraint matching any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Float is a constraint matching any floating-point type.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Number is a constraint matching any integer or floating-point type.
+type Number interface {
+	Integer | Float
+}
+
+// Int64 to String Conversion
+// Example usage:
+// str := Int64ToString(1541815603606036480)
+// fmt.Println("Converted string:", str)
+func Int64ToString(num int64) string {
+	return strconv.FormatInt(num, 10)
+}
+
+// Number to String Conversion for any integer or float type
+// Floats are formatted with the smallest precision that represents them exactly
+// Example usage:
+// str := NumberToString(uint8(42))
+// fmt.Println("Converted string:", str)
+func NumberToString[T Number](num T) string {
+	v := reflect.ValueOf(num)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	default:
+		return strconv.FormatUint(v.Uint(), 10)
+	}
+}
@@ -0,0 +1,159 @@
+package goease
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContains(t *testing.T) {
+	strs := []string{"apple", "banana", "cherry"}
+	if !Contains(strs, "banana") || Contains(strs, "grape") {
+		t.Error("unexpected result for string slice")
+	}
+	if !StringContains(strs, "cherry") {
+		t.Error("expected StringContains to find cherry")
+	}
+
+	ints := []int{10, 20, 30}
+	if !Contains(ints, 20) || Contains(ints, 25) {
+		t.Error("unexpected result for int slice")
+	}
+	if !IntContains(ints, 30) {
+		t.Error("expected IntContains to find 30")
+	}
+
+	type point struct{ X, Y int }
+	points := []point{{1, 2}, {3, 4}}
+	if !Contains(points, point{3, 4}) || Contains(points, point{2, 1}) {
+		t.Error("unexpected result for struct slice")
+	}
+}
+
+func TestUnique(t *testing.T) {
+	result := Unique([]string{"go", "db", "go", "api", "db", "go"})
+	if !reflect.DeepEqual(result, []string{"go", "db", "api"}) {
+		t.Errorf("unexpected result %v", result)
+	}
+
+	result = Unique([]string{"c", "a", "b"})
+	if !reflect.DeepEqual(result, []string{"c", "a", "b"}) {
+		t.Errorf("expected order to be preserved got %v", result)
+	}
+
+	if Unique([]int(nil)) != nil {
+		t.Error("expected nil result for nil input")
+	}
+}
+
+func TestStringToInt64(t *testing.T) {
+	n, err := StringToInt64("1541815603606036480")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1541815603606036480 {
+		t.Errorf("unexpected result %d", n)
+	}
+
+	n, err = StringToInt64("-9223372036854775808")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != -9223372036854775808 {
+		t.Errorf("unexpected result %d", n)
+	}
+
+	for _, input := range []string{"", "12a", "9223372036854775808"} {
+		if _, err := StringToInt64(input); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestStringToUint64(t *testing.T) {
+	n, err := StringToUint64("18446744073709551615")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 18446744073709551615 {
+		t.Errorf("unexpected result %d", n)
+	}
+
+	for _, input := range []string{"", "-1", "0x10", "18446744073709551616"} {
+		if _, err := StringToUint64(input); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestParseIntDefault(t *testing.T) {
+	tests := map[string]int{
+		"42":   42,
+		" -7 ": -7,
+		"abc":  10,
+		"4.2":  10,
+		"":     10,
+	}
+	for input, expected := range tests {
+		if got := ParseIntDefault(input, 10); got != expected {
+			t.Errorf("%q: expected %d got %d", input, expected, got)
+		}
+	}
+}
+
+func TestParseBoolDefault(t *testing.T) {
+	if !ParseBoolDefault("true", false) || !ParseBoolDefault(" yes ", false) {
+		t.Error("expected valid true values to be parsed")
+	}
+	if ParseBoolDefault("off", true) {
+		t.Error("expected valid false value to be parsed")
+	}
+	if !ParseBoolDefault("maybe", true) || ParseBoolDefault("maybe", false) {
+		t.Error("expected default value for invalid input")
+	}
+	if !ParseBoolDefault("", true) {
+		t.Error("expected default value for empty input")
+	}
+}
+
+func TestNumberToString(t *testing.T) {
+	if s := Int64ToString(-1541815603606036480); s != "-1541815603606036480" {
+		t.Errorf("unexpected result %q", s)
+	}
+
+	tests := []struct {
+		got      string
+		expected string
+	}{
+		{NumberToString(int64(9223372036854775807)), "9223372036854775807"},
+		{NumberToString(uint(42)), "42"},
+		{NumberToString(uint64(18446744073709551615)), "18446744073709551615"},
+		{NumberToString(int8(-8)), "-8"},
+		{NumberToString(123.456), "123.456"},
+		{NumberToString(float32(0.1)), "0.1"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("expected %q got %q", tt.expected, tt.got)
+		}
+	}
+}
+
+func TestJoinNumbers(t *testing.T) {
+	tests := []struct {
+		got      string
+		expected string
+	}{
+		{JoinInts([]int{1, 2, 3}, ", "), "1, 2, 3"},
+		{JoinNumbers([]int{1, 2, 3}, ","), "1,2,3"},
+		{JoinNumbers([]int64{1541815603606036480, -1}, "|"), "1541815603606036480|-1"},
+		{JoinNumbers([]float64{9.99, 19.5, 100}, ", "), "9.99, 19.5, 100"},
+		{JoinNumbers([]int{42}, ", "), "42"},
+		{JoinNumbers([]int{}, ", "), ""},
+		{JoinInts(nil, ", "), ""},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("expected %q got %q", tt.expected, tt.got)
+		}
+	}
+}
@@ -1,6 +1,12 @@
 package goease
 
-import "time"
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // ParseRFC3339Date parses a date string in RFC3339 format.
 //
@@ -10,20 +16,33 @@ import "time"
 // Returns:
 //   - time.Time: The parsed time if successful, otherwise a zero time.
 func ParseRFC3339Date(dateStr string) time.Time {
-	if dateStr == "" {
-		return time.Time{} // Return a zero time for empty string
-	}
-
-	parsedTime, err := time.Parse(time.RFC3339, dateStr)
+	parsedTime, err := ParseRFC3339DateStrict(dateStr)
 	if err != nil {
-		// Handle the error here, depending on your application logic
-		// For example, log the error or return a default value
 		return time.Time{} // Return a zero time for invalid input
 	}
 
 	return parsedTime
 }
 
+// ParseRFC3339DateStrict parses a date string in RFC3339 format, reporting parse failures.
+//
+// Unlike ParseRFC3339Date, a malformed date string is reported as an error, so that
+// "no date" (an empty string) can be told apart from "bad date".
+//
+// Parameters:
+//   - dateStr: string - The date string to parse.
+//
+// Returns:
+//   - time.Time: The parsed time, or a zero time for an empty string.
+//   - error: An error if the date string is not empty and not in RFC3339 format.
+func ParseRFC3339DateStrict(dateStr string) (time.Time, error) {
+	if dateStr == "" {
+		return time.Time{}, nil // Return a zero time for empty string
+	}
+
+	return time.Parse(time.RFC3339, dateStr)
+}
+
 // ParseCustomDate parses a date string in a custom format.
 //
 // Parameters:
@@ -57,3 +76,315 @@ func ParseCustomDate(dateStr, layout string) time.Time {
 func ParseISO8601Date(dateStr string) time.Time {
 	return ParseCustomDate(dateStr, "2006-01-02T15:04:05Z07:00")
 }
+
+// httpDateFormats lists the date formats accepted in HTTP headers as defined
+// by RFC 7231 section 7.1.1.1, starting with the preferred IMF-fixdate form.
+var httpDateFormats = []string{
+	"Mon, 02 Jan 2006 15:04:05 GMT",
+	time.RFC850,
+	time.ANSIC,
+}
+
+// ParseHTTPDate parses a date string from an HTTP header.
+//
+// Parameters:
+//   - s: string - The date string in RFC1123, RFC850 or ANSI C asctime format.
+//
+// Returns:
+//   - time.Time: The parsed time in UTC if successful, otherwise a zero time.
+//   - error: An error if the string does not match any of the HTTP date formats.
+func ParseHTTPDate(s string) (time.Time, error) {
+	for _, layout := range httpDateFormats {
+		if parsedTime, err := time.Parse(layout, s); err == nil {
+			return parsedTime.UTC(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid HTTP date: %q", s)
+}
+
+// FormatHTTPDate formats a time in the preferred HTTP date format (RFC1123 in GMT).
+//
+// Parameters:
+//   - t: time.Time - The time to format.
+//
+// Returns:
+//   - string: The formatted date, e.g. "Sun, 06 Nov 1994 08:49:37 GMT".
+func FormatHTTPDate(t time.Time) string {
+	return t.UTC().Format(httpDateFormats[0])
+}
+
+// RangesOverlap reports whether two time ranges overlap.
+//
+// Ranges that only touch at their boundaries (e.g. end1 equals start2) are
+// considered non-overlapping.
+//
+// Parameters:
+//   - start1, end1: time.Time - The bounds of the first range.
+//   - start2, end2: time.Time - The bounds of the second range.
+//
+// Returns:
+//   - bool: true if the ranges overlap, false otherwise.
+func RangesOverlap(start1, end1, start2, end2 time.Time) bool {
+	return start1.Before(end2) && start2.Before(end1)
+}
+
+// OverlapDuration returns how long two time ranges overlap.
+//
+// Parameters:
+//   - start1, end1: time.Time - The bounds of the first range.
+//   - start2, end2: time.Time - The bounds of the second range.
+//
+// Returns:
+//   - time.Duration: The duration of the overlap, or zero if the ranges do not overlap.
+func OverlapDuration(start1, end1, start2, end2 time.Time) time.Duration {
+	if !RangesOverlap(start1, end1, start2, end2) {
+		return 0
+	}
+
+	start := start1
+	if start2.After(start) {
+		start = start2
+	}
+	end := end1
+	if end2.Before(end) {
+		end = end2
+	}
+
+	return end.Sub(start)
+}
+
+// TimeIt runs a function and measures how long it took.
+//
+// Parameters:
+//   - fn: func() - The function to run.
+//
+// Returns:
+//   - time.Duration: The elapsed time.
+func TimeIt(fn func()) time.Duration {
+	start := time.Now()
+	fn()
+	return time.Since(start)
+}
+
+// TimeItErr runs a function returning an error and measures how long it took.
+//
+// Parameters:
+//   - fn: func() error - The function to run.
+//
+// Returns:
+//   - time.Duration: The elapsed time.
+//   - error: The error returned by the function.
+func TimeItErr(fn func() error) (time.Duration, error) {
+	start := time.Now()
+	err := fn()
+	return time.Since(start), err
+}
+
+// TransferETA estimates the time left to transfer the remaining bytes at a given rate.
+//
+// Parameters:
+//   - bytesRemaining: int64 - The number of bytes left to transfer. A non-positive value yields a zero duration.
+//   - bytesPerSecond: float64 - The current transfer rate.
+//
+// Returns:
+//   - time.Duration: The estimated remaining time.
+//   - error: An error if the rate is not positive.
+func TransferETA(bytesRemaining int64, bytesPerSecond float64) (time.Duration, error) {
+	if !(bytesPerSecond > 0) {
+		return 0, fmt.Errorf("transfer rate must be positive, got %v", bytesPerSecond)
+	}
+	if bytesRemaining <= 0 {
+		return 0, nil
+	}
+
+	return time.Duration(float64(bytesRemaining) / bytesPerSecond * float64(time.Second)), nil
+}
+
+// TimeAgo returns a human-readable description of a time relative to now.
+//
+// Parameters:
+//   - t: time.Time - The time to describe.
+//
+// Returns:
+//   - string: A relative description such as "just now", "5 minutes ago", "2 days ago" or, for future times, "in 3 months".
+func TimeAgo(t time.Time) string {
+	return timeAgo(t, time.Now())
+}
+
+// timeAgo describes t relative to the given reference time.
+func timeAgo(t, now time.Time) string {
+	diff := now.Sub(t)
+	future := diff < 0
+	if future {
+		diff = -diff
+	}
+
+	if diff < 10*time.Second {
+		return "just now"
+	}
+
+	var amount int64
+	var unit string
+	switch {
+	case diff < time.Minute:
+		amount, unit = int64(diff/time.Second), "second"
+	case diff < time.Hour:
+		amount, unit = int64(diff/time.Minute), "minute"
+	case diff < 24*time.Hour:
+		amount, unit = int64(diff/time.Hour), "hour"
+	case diff < 30*24*time.Hour:
+		amount, unit = int64(diff/(24*time.Hour)), "day"
+	case diff < 365*24*time.Hour:
+		amount, unit = int64(diff/(30*24*time.Hour)), "month"
+	default:
+		amount, unit = int64(diff/(365*24*time.Hour)), "year"
+	}
+	if amount != 1 {
+		unit += "s"
+	}
+
+	if future {
+		return fmt.Sprintf("in %d %s", amount, unit)
+	}
+	return fmt.Sprintf("%d %s ago", amount, unit)
+}
+
+// ConvertToTimezone converts a time to the given IANA time zone.
+//
+// Parameters:
+//   - t: time.Time - The time to convert.
+//   - tzName: string - The IANA time zone name, e.g. "Asia/Bangkok".
+//
+// Returns:
+//   - time.Time: The same instant expressed in the given time zone.
+//   - error: An error if the time zone name is unknown.
+func ConvertToTimezone(t time.Time, tzName string) (time.Time, error) {
+	loc, err := time.LoadLocation(tzName)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return t.In(loc), nil
+}
+
+// StartOfDay returns midnight at the start of the day of t, in the location of t.
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfDay returns the last nanosecond (23:59:59.999999999) of the day of t, in the location of t.
+func EndOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, t.Location()).Add(-time.Nanosecond)
+}
+
+// StartOfMonth returns midnight on the first day of the month of t, in the location of t.
+func StartOfMonth(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfMonth returns the last nanosecond of the last day of the month of t, in the location of t.
+func EndOfMonth(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	return time.Date(y, m+1, 1, 0, 0, 0, 0, t.Location()).Add(-time.Nanosecond)
+}
+
+// extendedDurationUnitRX matches the day and week components of an extended duration string.
+var extendedDurationUnitRX = regexp.MustCompile(`([0-9]*\.?[0-9]+)([dw])`)
+
+// ParseExtendedDuration parses a duration string like time.ParseDuration, with
+// additional support for "d" (24 hours) and "w" (7 days) units.
+//
+// Parameters:
+//   - s: string - The duration string, e.g. "30d", "2w", "1d12h" or "1h30m".
+//
+// Returns:
+//   - time.Duration: The parsed duration.
+//   - error: An error if the string is not a valid duration.
+func ParseExtendedDuration(s string) (time.Duration, error) {
+	str := strings.TrimSpace(s)
+	str, negative := strings.CutPrefix(str, "-")
+	if !negative {
+		str, _ = strings.CutPrefix(str, "+")
+	}
+	// Only a single leading sign is allowed, as with time.ParseDuration.
+	if strings.ContainsAny(str, "+-") {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	matches := extendedDurationUnitRX.FindAllStringSubmatch(str, -1)
+	var extended time.Duration
+	for _, match := range matches {
+		value, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		unit := 24 * time.Hour
+		if match[2] == "w" {
+			unit *= 7
+		}
+		extended += time.Duration(value * float64(unit))
+	}
+
+	rest := extendedDurationUnitRX.ReplaceAllString(str, "")
+	var d time.Duration
+	if rest != "" || len(matches) == 0 {
+		var err error
+		if d, err = time.ParseDuration(rest); err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+	}
+
+	d += extended
+	if negative {
+		d = -d
+	}
+	return d, nil
+}
+
+// durationUnits lists the units used by DurationLabel, from the largest to the smallest.
+var durationUnits = []struct {
+	size time.Duration
+	name string
+}{
+	{24 * time.Hour, "day"},
+	{time.Hour, "hour"},
+	{time.Minute, "minute"},
+	{time.Second, "second"},
+	{time.Millisecond, "millisecond"},
+	{time.Microsecond, "microsecond"},
+	{time.Nanosecond, "nanosecond"},
+}
+
+// DurationLabel expresses a duration in the largest unit it holds at least once.
+//
+// Parameters:
+//   - d: time.Duration - The duration to express.
+//
+// Returns:
+//   - value: float64 - The duration in the chosen unit, e.g. 2.5 for 2h30m.
+//   - unit: string - The unit name, singular when value is exactly 1 or -1 and plural otherwise, e.g. "hours".
+func DurationLabel(d time.Duration) (value float64, unit string) {
+	abs := d
+	if abs < 0 {
+		abs = -abs
+	}
+
+	chosen := durationUnits[len(durationUnits)-1]
+	for _, u := range durationUnits {
+		if abs >= u.size {
+			chosen = u
+			break
+		}
+	}
+
+	value = float64(d) / float64(chosen.size)
+	unit = chosen.name
+	if value != 1 && value != -1 {
+		unit += "s"
+	}
+	return value, unit
+}
@@ -0,0 +1,281 @@
+package goease
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseHTTPDate(t *testing.T) {
+	expected := time.Date(1994, time.November, 6, 8, 49, 37, 0, time.UTC)
+
+	inputs := []string{
+		"Sun, 06 Nov 1994 08:49:37 GMT",  // RFC1123
+		"Sunday, 06-Nov-94 08:49:37 GMT", // RFC850
+		"Sun Nov  6 08:49:37 1994",       // ANSI C asctime
+	}
+	for _, input := range inputs {
+		parsed, err := ParseHTTPDate(input)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", input, err)
+			continue
+		}
+		if !parsed.Equal(expected) {
+			t.Errorf("expected %v for %q got %v", expected, input, parsed)
+		}
+	}
+
+	if _, err := ParseHTTPDate("1994-11-06T08:49:37Z"); err == nil {
+		t.Error("expected error for non HTTP date")
+	}
+}
+
+func TestFormatHTTPDate(t *testing.T) {
+	loc := time.FixedZone("UTC+7", 7*60*60)
+	input := time.Date(1994, time.November, 6, 15, 49, 37, 0, loc)
+
+	if formatted := FormatHTTPDate(input); formatted != "Sun, 06 Nov 1994 08:49:37 GMT" {
+		t.Errorf("unexpected formatted date %q", formatted)
+	}
+}
+
+func TestRangesOverlap(t *testing.T) {
+	base := time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+	hour := func(h int) time.Time { return base.Add(time.Duration(h) * time.Hour) }
+
+	tests := []struct {
+		name                       string
+		start1, end1, start2, end2 time.Time
+		overlap                    bool
+		duration                   time.Duration
+	}{
+		{"overlapping", hour(0), hour(2), hour(1), hour(3), true, time.Hour},
+		{"contained", hour(0), hour(4), hour(1), hour(2), true, time.Hour},
+		{"disjoint", hour(0), hour(1), hour(2), hour(3), false, 0},
+		{"touching", hour(0), hour(1), hour(1), hour(2), false, 0},
+	}
+	for _, tt := range tests {
+		if got := RangesOverlap(tt.start1, tt.end1, tt.start2, tt.end2); got != tt.overlap {
+			t.Errorf("%s: expected overlap %v got %v", tt.name, tt.overlap, got)
+		}
+		if got := OverlapDuration(tt.start1, tt.end1, tt.start2, tt.end2); got != tt.duration {
+			t.Errorf("%s: expected duration %v got %v", tt.name, tt.duration, got)
+		}
+	}
+}
+
+func TestTimeIt(t *testing.T) {
+	elapsed := TimeIt(func() { time.Sleep(20 * time.Millisecond) })
+	if elapsed < 20*time.Millisecond {
+		t.Errorf("expected at least 20ms got %v", elapsed)
+	}
+
+	errBoom := errors.New("boom")
+	elapsed, err := TimeItErr(func() error {
+		time.Sleep(20 * time.Millisecond)
+		return errBoom
+	})
+	if err != errBoom {
+		t.Errorf("expected error %v got %v", errBoom, err)
+	}
+	if elapsed < 20*time.Millisecond {
+		t.Errorf("expected at least 20ms got %v", elapsed)
+	}
+}
+
+func TestTransferETA(t *testing.T) {
+	eta, err := TransferETA(10*1024*1024, 1024*1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eta != 10*time.Second {
+		t.Errorf("expected 10s got %v", eta)
+	}
+
+	eta, err = TransferETA(0, 1024)
+	if err != nil || eta != 0 {
+		t.Errorf("expected zero duration got %v, %v", eta, err)
+	}
+
+	if _, err := TransferETA(1024, 0); err == nil {
+		t.Error("expected error for zero rate")
+	}
+	if _, err := TransferETA(1024, -1); err == nil {
+		t.Error("expected error for negative rate")
+	}
+}
+
+func TestTimeAgo(t *testing.T) {
+	now := time.Date(2024, time.June, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		t        time.Time
+		expected string
+	}{
+		{now.Add(-3 * time.Second), "just now"},
+		{now.Add(-30 * time.Second), "30 seconds ago"},
+		{now.Add(-time.Minute), "1 minute ago"},
+		{now.Add(-5 * time.Minute), "5 minutes ago"},
+		{now.Add(-3 * time.Hour), "3 hours ago"},
+		{now.Add(-2 * 24 * time.Hour), "2 days ago"},
+		{now.Add(-95 * 24 * time.Hour), "3 months ago"},
+		{now.Add(-400 * 24 * time.Hour), "1 year ago"},
+		{now.Add(5 * time.Minute), "in 5 minutes"},
+		{now.Add(2 * 24 * time.Hour), "in 2 days"},
+	}
+	for _, tt := range tests {
+		if got := timeAgo(tt.t, now); got != tt.expected {
+			t.Errorf("expected %q got %q", tt.expected, got)
+		}
+	}
+
+	if got := TimeAgo(time.Now()); got != "just now" {
+		t.Errorf("expected \"just now\" got %q", got)
+	}
+}
+
+func TestParseRFC3339DateStrict(t *testing.T) {
+	parsed, err := ParseRFC3339DateStrict("2024-01-02T15:04:05+07:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := time.Date(2024, time.January, 2, 8, 4, 5, 0, time.UTC); !parsed.Equal(expected) {
+		t.Errorf("expected %v got %v", expected, parsed)
+	}
+
+	if _, err := ParseRFC3339DateStrict("not-a-date"); err == nil {
+		t.Error("expected error for malformed date")
+	}
+	if !ParseRFC3339Date("not-a-date").IsZero() {
+		t.Error("expected lenient version to return a zero time")
+	}
+
+	parsed, err = ParseRFC3339DateStrict("")
+	if err != nil || !parsed.IsZero() {
+		t.Errorf("expected zero time without error for empty string got %v, %v", parsed, err)
+	}
+}
+
+func TestConvertToTimezone(t *testing.T) {
+	utc := time.Date(2024, time.January, 15, 17, 0, 0, 0, time.UTC)
+
+	converted, err := ConvertToTimezone(utc, "America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !converted.Equal(utc) {
+		t.Errorf("expected the same instant got %v", converted)
+	}
+	if converted.Hour() != 12 || converted.Location().String() != "America/New_York" {
+		t.Errorf("expected 12:00 in America/New_York got %v", converted)
+	}
+
+	if _, err := ConvertToTimezone(utc, "Mars/Olympus_Mons"); err == nil {
+		t.Error("expected error for unknown time zone")
+	}
+}
+
+func TestDayBoundaries(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2024-03-10 is the spring forward DST transition, a 23 hours day.
+	input := time.Date(2024, time.March, 10, 15, 30, 0, 0, loc)
+
+	start := StartOfDay(input)
+	if expected := time.Date(2024, time.March, 10, 0, 0, 0, 0, loc); !start.Equal(expected) || start.Location() != loc {
+		t.Errorf("expected %v got %v", expected, start)
+	}
+
+	end := EndOfDay(input)
+	if expected := time.Date(2024, time.March, 10, 23, 59, 59, 999999999, loc); !end.Equal(expected) || end.Location() != loc {
+		t.Errorf("expected %v got %v", expected, end)
+	}
+	if length := end.Sub(start); length != 23*time.Hour-time.Nanosecond {
+		t.Errorf("expected a 23 hours day got %v", length)
+	}
+}
+
+func TestMonthBoundaries(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leap := time.Date(2024, time.February, 15, 12, 0, 0, 0, loc)
+	if expected := time.Date(2024, time.February, 1, 0, 0, 0, 0, loc); !StartOfMonth(leap).Equal(expected) {
+		t.Errorf("expected %v got %v", expected, StartOfMonth(leap))
+	}
+	if expected := time.Date(2024, time.February, 29, 23, 59, 59, 999999999, loc); !EndOfMonth(leap).Equal(expected) {
+		t.Errorf("expected %v got %v", expected, EndOfMonth(leap))
+	}
+
+	nonLeap := time.Date(2023, time.February, 15, 12, 0, 0, 0, time.UTC)
+	if expected := time.Date(2023, time.February, 28, 23, 59, 59, 999999999, time.UTC); !EndOfMonth(nonLeap).Equal(expected) {
+		t.Errorf("expected %v got %v", expected, EndOfMonth(nonLeap))
+	}
+
+	december := time.Date(2023, time.December, 31, 23, 0, 0, 0, time.UTC)
+	if expected := time.Date(2023, time.December, 31, 23, 59, 59, 999999999, time.UTC); !EndOfMonth(december).Equal(expected) {
+		t.Errorf("expected %v got %v", expected, EndOfMonth(december))
+	}
+}
+
+func TestParseExtendedDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"30d", 30 * 24 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"1h30m", 90 * time.Minute},
+		{"1w2d3h", 9*24*time.Hour + 3*time.Hour},
+		{"1.5d", 36 * time.Hour},
+		{"-1d", -24 * time.Hour},
+		{"+1d", 24 * time.Hour},
+		{"-1d12h", -36 * time.Hour},
+		{"0", 0},
+		{"0d", 0},
+	}
+	for _, tt := range tests {
+		got, err := ParseExtendedDuration(tt.input)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", tt.input, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("%q: expected %v got %v", tt.input, tt.expected, got)
+		}
+	}
+
+	for _, input := range []string{"", "abc", "3x", "d", "+-1d", "--1h", "++1d", "1d-2h", "1h+2m", "-"} {
+		if _, err := ParseExtendedDuration(input); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestDurationLabel(t *testing.T) {
+	tests := []struct {
+		d     time.Duration
+		value float64
+		unit  string
+	}{
+		{250 * time.Millisecond, 250, "milliseconds"},
+		{1500 * time.Microsecond, 1.5, "milliseconds"},
+		{90 * time.Second, 1.5, "minutes"},
+		{time.Minute, 1, "minute"},
+		{150 * time.Minute, 2.5, "hours"},
+		{-3 * time.Hour, -3, "hours"},
+		{36 * time.Hour, 1.5, "days"},
+		{0, 0, "nanoseconds"},
+	}
+	for _, tt := range tests {
+		value, unit := DurationLabel(tt.d)
+		if value != tt.value || unit != tt.unit {
+			t.Errorf("%v: expected (%v, %q) got (%v, %q)", tt.d, tt.value, tt.unit, value, unit)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package goease
+
+import "fmt"
+
+// ContextError is an error carrying a JSONB payload describing the context it occurred in,
+// e.g. the request ID or the user ID of a failing handler.
+//
+// It wraps the original error, so errors.Is and errors.As still match it.
+type ContextError struct {
+	Err     error
+	Context JSONB
+}
+
+// Error returns the message of the wrapped error followed by its context, serialized with MarshalJSONBCanonical
+// so that the same context always gives the same message.
+func (e *ContextError) Error() string {
+	if len(e.Context) == 0 {
+		return e.Err.Error()
+	}
+
+	context, err := MarshalJSONBCanonical(e.Context)
+	if err != nil {
+		return fmt.Sprintf("%v (context: %v)", e.Err, map[string]interface{}(e.Context))
+	}
+	return fmt.Sprintf("%v (context: %s)", e.Err, context)
+}
+
+// Unwrap returns the wrapped error.
+func (e *ContextError) Unwrap() error {
+	return e.Err
+}
+
+// WrapError attaches a JSONB context to an error.
+//
+// Parameters:
+//   - err: error - The error to wrap. A nil error returns nil, so the result can be returned as is.
+//   - ctx: JSONB - The context of the error.
+//
+// Returns:
+//   - error: A *ContextError wrapping err, or nil.
+//
+// Example:
+//
+//	if err := saveOrder(order); err != nil {
+//	    return WrapError(err, JSONB{"order_id": order.ID, "user_id": userID})
+//	}
+//
+// The resulting message looks like `failed to save order (context: {"order_id":42,"user_id":7})`.
+func WrapError(err error, ctx JSONB) error {
+	if err == nil {
+		return nil
+	}
+	return &ContextError{Err: err, Context: ctx}
+}
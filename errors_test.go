@@ -0,0 +1,36 @@
+package goease
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+)
+
+func TestWrapError(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "config.json", Err: fs.ErrNotExist}
+	err := WrapError(pathErr, JSONB{"user_id": 7, "request_id": "abc"})
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Error("expected errors.Is to match the wrapped error")
+	}
+	var target *fs.PathError
+	if !errors.As(err, &target) || target.Path != "config.json" {
+		t.Error("expected errors.As to find the wrapped error")
+	}
+	var contextErr *ContextError
+	if !errors.As(err, &contextErr) || contextErr.Context["user_id"] != 7 {
+		t.Error("expected errors.As to find the context error")
+	}
+
+	expected := `open config.json: file does not exist (context: {"request_id":"abc","user_id":7})`
+	if err.Error() != expected {
+		t.Errorf("expected %q got %q", expected, err.Error())
+	}
+
+	if got := WrapError(pathErr, nil).Error(); got != pathErr.Error() {
+		t.Errorf("expected the message of the wrapped error got %q", got)
+	}
+	if WrapError(nil, JSONB{"user_id": 7}) != nil {
+		t.Error("expected nil for a nil error")
+	}
+}
@@ -105,6 +105,25 @@ func ArgonComparePasswordAndHash(password, hash string) (match bool, err error)
 	return match, err
 }
 
+// ComparePasswordAndHashConstantTime is like ComparePasswordAndHash, except it
+// never returns early when the hash is empty or malformed. In that case a dummy
+// Argon2id key is still derived using ArgonDefaultParams before returning
+// false, so that comparing against a missing or invalid hash takes roughly the
+// same time as a real comparison. This helps prevent user enumeration through
+// timing. Decode errors are not returned, the result is simply false.
+func ArgonComparePasswordAndHashConstantTime(password, hash string) (match bool, err error) {
+	params, salt, key, err := ArgonDecodeHash(hash)
+	if err != nil {
+		salt = make([]byte, ArgonDefaultParams.SaltLength)
+		argon2.IDKey([]byte(password), salt, ArgonDefaultParams.Iterations, ArgonDefaultParams.Memory, ArgonDefaultParams.Parallelism, ArgonDefaultParams.KeyLength)
+		return false, nil
+	}
+
+	otherKey := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+
+	return subtle.ConstantTimeCompare(key, otherKey) == 1, nil
+}
+
 // CheckHash is like ComparePasswordAndHash, except it also returns the params that the hash was
 // created with. This can be useful if you want to update your hash params over time (which you
 // should).
@@ -179,3 +198,21 @@ func ArgonDecodeHash(hash string) (params *ArgonParams, salt, key []byte, err er
 
 	return params, salt, key, nil
 }
+
+// NeedsRehash reports whether a hash created from this package was generated
+// with parameters weaker than the provided target params. It is intended to be
+// called after a successful login so that stored hashes can be upgraded when
+// the params are increased over time. Parallelism is not taken into account as
+// it does not change the cost of an attack, only how the work is spread across
+// threads.
+func ArgonNeedsRehash(hash string, params *ArgonParams) (bool, error) {
+	current, _, _, err := ArgonDecodeHash(hash)
+	if err != nil {
+		return false, err
+	}
+
+	return current.Memory < params.Memory ||
+		current.Iterations < params.Iterations ||
+		current.SaltLength < params.SaltLength ||
+		current.KeyLength < params.KeyLength, nil
+}
@@ -116,4 +116,97 @@ func TestVariant(t *testing.T) {
 	if err != ArgonErrIncompatibleVariant {
 		t.Fatalf("expected error %s", ArgonErrIncompatibleVariant)
 	}
-}
\ No newline at end of file
+}
+
+func TestNeedsRehash(t *testing.T) {
+	hash, err := ArgonCreateHash("pa$$word", ArgonDefaultParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rehash, err := ArgonNeedsRehash(hash, ArgonDefaultParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rehash {
+		t.Error("expected no rehash when params match")
+	}
+
+	target := *ArgonDefaultParams
+	target.Memory *= 2
+	rehash, err = ArgonNeedsRehash(hash, &target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rehash {
+		t.Error("expected rehash when memory is lower than target")
+	}
+
+	_, err = ArgonNeedsRehash("not-a-hash", ArgonDefaultParams)
+	if err != ArgonErrInvalidHash {
+		t.Fatalf("expected error %s", ArgonErrInvalidHash)
+	}
+}
+
+func TestCustomSaltAndKeyLength(t *testing.T) {
+	params := *ArgonDefaultParams
+	params.SaltLength = 32
+	params.KeyLength = 64
+
+	hash, err := ArgonCreateHash("pa$$word", &params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, salt, key, err := ArgonDecodeHash(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(salt) != 32 || len(key) != 64 {
+		t.Fatalf("expected salt of 32 bytes and key of 64 bytes got %d and %d", len(salt), len(key))
+	}
+	if *decoded != params {
+		t.Fatalf("expected %#v got %#v", params, *decoded)
+	}
+
+	match, err := ArgonComparePasswordAndHash("pa$$word", hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !match {
+		t.Error("expected password and hash to match")
+	}
+}
+
+func TestComparePasswordAndHashConstantTime(t *testing.T) {
+	hash, err := ArgonCreateHash("pa$$word", ArgonDefaultParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	match, err := ArgonComparePasswordAndHashConstantTime("pa$$word", hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !match {
+		t.Error("expected password and hash to match")
+	}
+
+	match, err = ArgonComparePasswordAndHashConstantTime("otherPa$$word", hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match {
+		t.Error("expected password and hash to not match")
+	}
+
+	for _, stored := range []string{"", "not-a-hash"} {
+		match, err = ArgonComparePasswordAndHashConstantTime("pa$$word", stored)
+		if err != nil {
+			t.Fatalf("expected no error for stored hash %q got %s", stored, err)
+		}
+		if match {
+			t.Errorf("expected stored hash %q to not match", stored)
+		}
+	}
+}
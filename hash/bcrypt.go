@@ -0,0 +1,37 @@
+package goease
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptCreateHash returns a bcrypt hash of a plain-text password using the
+// provided cost. A cost outside of the range bcrypt.MinCost to bcrypt.MaxCost
+// is rejected by bcrypt itself. The returned hash is in the modular crypt
+// format and looks like this:
+//
+//	$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy
+func BcryptCreateHash(password string, cost int) (hash string, err error) {
+	b, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// BcryptComparePasswordAndHash compares a plain-text password with a bcrypt
+// hash. It returns true if they match, otherwise it returns false. An error is
+// only returned if the hash itself is malformed.
+func BcryptComparePasswordAndHash(password, hash string) (match bool, err error) {
+	err = bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
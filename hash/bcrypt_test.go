@@ -0,0 +1,56 @@
+package goease
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestBcryptCreateHash(t *testing.T) {
+	hash, err := BcryptCreateHash("pa$$word", bcrypt.MinCost+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost != bcrypt.MinCost+1 {
+		t.Fatalf("expected cost %d got %d", bcrypt.MinCost+1, cost)
+	}
+
+	match, err := BcryptComparePasswordAndHash("pa$$word", hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !match {
+		t.Error("expected password and hash to match")
+	}
+}
+
+func TestBcryptComparePasswordAndHash(t *testing.T) {
+	// "pa$$word" hashed with cost 4
+	hash := "$2a$04$sYMR6Qey42BEw7Acu8ueUO6WvYq29WgfuLtsXjO2hjB30S50REZvi"
+
+	match, err := BcryptComparePasswordAndHash("pa$$word", hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !match {
+		t.Error("expected password and hash to match")
+	}
+
+	match, err = BcryptComparePasswordAndHash("otherPa$$word", hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match {
+		t.Error("expected password and hash to not match")
+	}
+
+	_, err = BcryptComparePasswordAndHash("pa$$word", "not-a-hash")
+	if err == nil {
+		t.Error("expected error for malformed hash")
+	}
+}
@@ -0,0 +1,192 @@
+package goease
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseRangeHeader parses the value of an HTTP Range header for a resource of the given size.
+//
+// This function supports a single byte range in its three forms: "bytes=START-END", the open-ended "bytes=START-" and the suffix "bytes=-LENGTH" (the last LENGTH bytes). An END beyond the resource is clamped to its last byte, and a suffix longer than the resource selects the whole resource.
+//
+// Parameters:
+//   - header: string - The Range header value.
+//   - size: int64 - The size of the resource in bytes.
+//
+// Returns:
+//   - start: int64 - The offset of the first byte of the range.
+//   - end: int64 - The offset of the last byte of the range (inclusive), so the length is end - start + 1.
+//   - err: error - An error if the header is malformed, holds several ranges, or the range is not satisfiable (which should be answered with 416 Range Not Satisfiable).
+//
+// Example:
+//
+//	start, end, err := ParseRangeHeader("bytes=-500", 10000)
+//
+// This will return 9500 and 9999.
+func ParseRangeHeader(header string, size int64) (start, end int64, err error) {
+	spec, ok := strings.CutPrefix(strings.TrimSpace(header), "bytes=")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid range header: %q", header)
+	}
+	if strings.Contains(spec, ",") {
+		return 0, 0, fmt.Errorf("multiple ranges are not supported: %q", header)
+	}
+
+	startStr, endStr, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok || (startStr == "" && endStr == "") {
+		return 0, 0, fmt.Errorf("invalid range header: %q", header)
+	}
+
+	if startStr == "" {
+		length, err := strconv.ParseInt(endStr, 10, 64)
+		if err != nil || length < 0 {
+			return 0, 0, fmt.Errorf("invalid range header: %q", header)
+		}
+		if length == 0 || size <= 0 {
+			return 0, 0, fmt.Errorf("unsatisfiable range: %q", header)
+		}
+		if length > size {
+			length = size
+		}
+		return size - length, size - 1, nil
+	}
+
+	start, err = strconv.ParseInt(startStr, 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, fmt.Errorf("invalid range header: %q", header)
+	}
+	end = size - 1
+	if endStr != "" {
+		end, err = strconv.ParseInt(endStr, 10, 64)
+		if err != nil || end < start {
+			return 0, 0, fmt.Errorf("invalid range header: %q", header)
+		}
+		if end > size-1 {
+			end = size - 1
+		}
+	}
+	if start >= size {
+		return 0, 0, fmt.Errorf("unsatisfiable range: %q", header)
+	}
+
+	return start, end, nil
+}
+
+// ContentDisposition builds the value of a Content-Disposition header for downloading a file.
+//
+// The value holds both a quoted "filename" parameter, where non-ASCII and control characters are replaced by underscores for older clients, and a "filename*" parameter percent-encoded in UTF-8 as defined by RFC 5987, which modern clients prefer.
+//
+// Parameters:
+//   - filename: string - The name of the downloaded file.
+//
+// Returns:
+//   - string: The header value, holding the "attachment" type with both the filename and filename* parameters.
+func ContentDisposition(filename string) string {
+	var fallback strings.Builder
+	for _, r := range filename {
+		switch {
+		case r == '"' || r == '\\':
+			fallback.WriteByte('\\')
+			fallback.WriteRune(r)
+		case r < 0x20 || r >= 0x7f:
+			fallback.WriteByte('_')
+		default:
+			fallback.WriteRune(r)
+		}
+	}
+
+	var encoded strings.Builder
+	for _, b := range []byte(filename) {
+		if isRFC5987AttrChar(b) {
+			encoded.WriteByte(b)
+		} else {
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+
+	return fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`, fallback.String(), encoded.String())
+}
+
+// isRFC5987AttrChar reports whether b can appear unencoded in an RFC 5987 extended parameter value.
+func isRFC5987AttrChar(b byte) bool {
+	switch {
+	case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", b) != -1
+}
+
+// SignURL signs a URL with an HMAC-SHA256 signature valid until the given expiry.
+//
+// This function adds an "expires" query parameter holding the expiry as a Unix timestamp, then a "signature" query parameter computed over the whole URL. It is typically used to hand out temporary download links.
+//
+// Parameters:
+//   - baseURL: string - The URL to sign. Existing query parameters are kept and covered by the signature.
+//   - expiry: time.Time - The time after which the URL is no longer valid.
+//   - secret: []byte - The secret key used for signing.
+//
+// Returns:
+//   - string: The signed URL.
+//   - error: An error if the URL cannot be parsed.
+func SignURL(baseURL string, expiry time.Time, secret []byte) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse url: %w", err)
+	}
+
+	query := u.Query()
+	query.Del("signature")
+	query.Set("expires", strconv.FormatInt(expiry.Unix(), 10))
+	u.RawQuery = query.Encode()
+
+	query.Set("signature", urlSignature(u.String(), secret))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// VerifySignedURL checks the signature and expiry of a URL signed with SignURL.
+//
+// Parameters:
+//   - rawURL: string - The signed URL.
+//   - secret: []byte - The secret key used for signing.
+//
+// Returns:
+//   - bool: true if the signature is valid and the URL has not expired, false otherwise.
+//   - error: An error if the URL cannot be parsed or lacks the "expires" or "signature" parameters.
+func VerifySignedURL(rawURL string, secret []byte) (bool, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse url: %w", err)
+	}
+
+	query := u.Query()
+	signature := query.Get("signature")
+	if signature == "" {
+		return false, fmt.Errorf("url has no signature")
+	}
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("url has no valid expires: %w", err)
+	}
+
+	query.Del("signature")
+	u.RawQuery = query.Encode()
+	if !hmac.Equal([]byte(signature), []byte(urlSignature(u.String(), secret))) {
+		return false, nil
+	}
+
+	return time.Now().Unix() <= expires, nil
+}
+
+// urlSignature returns the base64url-encoded HMAC-SHA256 of a URL.
+func urlSignature(rawURL string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(rawURL))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
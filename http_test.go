@@ -0,0 +1,110 @@
+package goease
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRangeHeader(t *testing.T) {
+	tests := []struct {
+		header     string
+		start, end int64
+	}{
+		{"bytes=0-499", 0, 499},
+		{"bytes=500-999", 500, 999},
+		{"bytes=9500-20000", 9500, 9999},
+		{"bytes=9500-", 9500, 9999},
+		{"bytes=-500", 9500, 9999},
+		{"bytes=-20000", 0, 9999},
+	}
+	for _, tt := range tests {
+		start, end, err := ParseRangeHeader(tt.header, 10000)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", tt.header, err)
+			continue
+		}
+		if start != tt.start || end != tt.end {
+			t.Errorf("%q: expected %d-%d got %d-%d", tt.header, tt.start, tt.end, start, end)
+		}
+	}
+
+	for _, header := range []string{
+		"",
+		"items=0-10",
+		"bytes=-",
+		"bytes=abc-10",
+		"bytes=500-100",
+		"bytes=0-10,20-30",
+		"bytes=10000-",
+		"bytes=-0",
+	} {
+		if _, _, err := ParseRangeHeader(header, 10000); err == nil {
+			t.Errorf("expected error for %q", header)
+		}
+	}
+}
+
+func TestContentDisposition(t *testing.T) {
+	tests := map[string]string{
+		"report.pdf":         `attachment; filename="report.pdf"; filename*=UTF-8''report.pdf`,
+		"my report 2024.pdf": `attachment; filename="my report 2024.pdf"; filename*=UTF-8''my%20report%202024.pdf`,
+		"résumé.pdf":         `attachment; filename="r_sum_.pdf"; filename*=UTF-8''r%C3%A9sum%C3%A9.pdf`,
+		"รายงาน.txt":         `attachment; filename="______.txt"; filename*=UTF-8''%E0%B8%A3%E0%B8%B2%E0%B8%A2%E0%B8%87%E0%B8%B2%E0%B8%99.txt`,
+		`say "hi"\now.txt`:   `attachment; filename="say \"hi\"\\now.txt"; filename*=UTF-8''say%20%22hi%22%5Cnow.txt`,
+	}
+	for input, expected := range tests {
+		if got := ContentDisposition(input); got != expected {
+			t.Errorf("%q: expected %s got %s", input, expected, got)
+		}
+	}
+}
+
+func TestSignURL(t *testing.T) {
+	secret := []byte("url-secret")
+
+	signed, err := SignURL("https://cdn.example.com/files/report.pdf?version=2", time.Now().Add(time.Hour), secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(signed, "expires=") || !strings.Contains(signed, "signature=") || !strings.Contains(signed, "version=2") {
+		t.Errorf("unexpected signed url %s", signed)
+	}
+
+	valid, err := VerifySignedURL(signed, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !valid {
+		t.Error("expected signed url to be valid")
+	}
+
+	if valid, _ := VerifySignedURL(signed, []byte("other-secret")); valid {
+		t.Error("expected url to be invalid with another secret")
+	}
+
+	tampered := strings.Replace(signed, "report.pdf", "secret.pdf", 1)
+	if valid, _ := VerifySignedURL(tampered, secret); valid {
+		t.Error("expected tampered path to be invalid")
+	}
+	tampered = strings.Replace(signed, "version=2", "version=3", 1)
+	if valid, _ := VerifySignedURL(tampered, secret); valid {
+		t.Error("expected tampered query to be invalid")
+	}
+
+	expired, err := SignURL("https://cdn.example.com/files/report.pdf", time.Now().Add(-time.Minute), secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid, err = VerifySignedURL(expired, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid {
+		t.Error("expected expired url to be invalid")
+	}
+
+	if _, err := VerifySignedURL("https://cdn.example.com/files/report.pdf", secret); err == nil {
+		t.Error("expected error for unsigned url")
+	}
+}
@@ -1,11 +1,24 @@
 package goease
 
 import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
 	"database/sql/driver"
+	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
+	"io"
 	"log"
+	"path"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
 )
 
 // JSONB represents a JSONB type typically used to store JSON data in databases.
@@ -96,6 +109,64 @@ func (j *JSONB) Scan(value interface{}) error {
 	return nil
 }
 
+// Entries returns the key/value pairs of the JSONB value sorted by key.
+//
+// This method is useful whenever a JSONB value has to be iterated in a deterministic order, since ranging over a map in Go yields its keys in random order.
+//
+// Returns:
+//   - []struct{ Key string; Value interface{} }: The entries of the JSONB value sorted by key in ascending order.
+//
+// Example:
+//
+//	jsonData := JSONB{"name": "John", "age": 30}
+//	for _, entry := range jsonData.Entries() {
+//	    fmt.Println(entry.Key, entry.Value)
+//	}
+//
+// This will print "age 30" followed by "name John".
+func (j JSONB) Entries() []struct {
+	Key   string
+	Value interface{}
+} {
+	keys := j.Keys()
+	entries := make([]struct {
+		Key   string
+		Value interface{}
+	}, len(keys))
+	for i, key := range keys {
+		entries[i].Key = key
+		entries[i].Value = j[key]
+	}
+
+	return entries
+}
+
+// Keys returns the keys of the JSONB value sorted alphabetically.
+//
+// Returns:
+//   - []string: The sorted keys.
+func (j JSONB) Keys() []string {
+	keys := make([]string, 0, len(j))
+	for key := range j {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Values returns the values of the JSONB value in the order of its sorted keys, so that Values()[i] is the value of Keys()[i].
+//
+// Returns:
+//   - []interface{}: The values ordered by key.
+func (j JSONB) Values() []interface{} {
+	keys := j.Keys()
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		values[i] = j[key]
+	}
+	return values
+}
+
 // ConvertToJSONB converts two input data structures into JSONB types.
 //
 // This function takes two input interfaces representing data structures and converts them into JSONB types, which are custom types typically used to represent JSON data in databases that support JSONB storage.
@@ -160,6 +231,42 @@ func ConvertToJSONB(oldData, newData interface{}) (JSONB, JSONB, error) {
 	return JSONB(oldDataMap), JSONB(newDataMap), nil
 }
 
+// ConvertToJSONBA converts two input slices into JSONBA types.
+//
+// This function is the counterpart of ConvertToJSONB for collections: it takes two input interfaces representing slices (e.g. []struct) and converts them into JSONBA types.
+//
+// Parameters:
+//   - oldData: interface{} - The old slice to be converted into a JSONBA type.
+//   - newData: interface{} - The new slice to be converted into a JSONBA type.
+//
+// Returns:
+//   - JSONBA: A JSONBA representation of the old slice.
+//   - JSONBA: A JSONBA representation of the new slice.
+//   - error: An error if there's any issue during the conversion process, such as an input that is not a slice of objects.
+//
+// Usage Example:
+//
+//	oldItems := []Item{{SKU: "A1", Qty: 1}}
+//	newItems := []Item{{SKU: "A1", Qty: 2}, {SKU: "B2", Qty: 1}}
+//	oldJSONBA, newJSONBA, err := ConvertToJSONBA(oldItems, newItems)
+//	if err != nil {
+//	    fmt.Println("Error:", err)
+//	    return
+//	}
+func ConvertToJSONBA(oldData, newData interface{}) (JSONBA, JSONBA, error) {
+	oldJSONBA, err := NewJSONBA(oldData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newJSONBA, err := NewJSONBA(newData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return oldJSONBA, newJSONBA, nil
+}
+
 // StructToMap converts a struct into a map[string]interface{}.
 //
 // This function takes an input interface{} representing a struct and converts it into a map where the keys are the field names of the struct, and the values are the corresponding field values. It is particularly useful when you need to work with data in a more dynamic or generic way.
@@ -229,6 +336,75 @@ func StructToMap(data interface{}) (map[string]interface{}, error) {
 	return result, nil
 }
 
+// StructToMapOmitEmpty converts a struct into a map[string]interface{}, leaving out the fields holding an empty value.
+//
+// A field is considered empty when it holds the zero value of its type, or an empty slice, map or array, so that API responses can be shaped without pruning them by hand.
+//
+// Parameters:
+//   - data: interface{} - The input value that should be a struct or a pointer to a struct.
+//
+// Returns:
+//   - map[string]interface{}: A map of the non-empty fields.
+//   - error: An error if the input is not a struct.
+//
+// Example:
+//
+//	type Person struct {
+//	    Name  string   `json:"name"`
+//	    Age   int      `json:"age"`
+//	    Tags  []string `json:"tags,omitempty"`
+//	}
+//
+//	personMap, err := StructToMapOmitEmpty(Person{Name: "John", Tags: []string{}})
+//
+// The 'personMap' will contain map[string]interface{}{"name": "John"}.
+//
+// Note:
+//   - Unlike StructToMap, the options of the json tags are stripped from the keys, fields tagged with "-" are skipped and unexported fields are ignored.
+func StructToMapOmitEmpty(data interface{}) (map[string]interface{}, error) {
+	value := reflect.ValueOf(data)
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("not a struct")
+	}
+
+	result := make(map[string]interface{})
+	typ := value.Type()
+	for i := 0; i < value.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		if fieldValue := value.Field(i); !isEmptyValue(fieldValue) {
+			result[name] = fieldValue.Interface()
+		}
+	}
+
+	return result, nil
+}
+
+// isEmptyValue reports whether v holds the zero value of its type, or an empty slice, map or array.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
 // ReadJSONB reads JSON data into the target interface.
 //
 // This function unmarshals the JSON data contained in the 'jsonData' byte slice into the provided 'target' interface{}. The 'target' must be a pointer to the type into which the JSON data will be unmarshaled. If the unmarshaling process encounters an error, it returns that error. Otherwise, it returns nil.
@@ -259,6 +435,42 @@ func ReadJSONB(jsonData []byte, target interface{}) error {
 	return nil
 }
 
+// ReadJSONBStrict reads JSON data into the target interface, rejecting unknown fields.
+//
+// This function behaves like ReadJSONB, but returns an error when the JSON data holds a field that does not exist in the target struct, or trailing data after the JSON value. It is meant for validating inbound API payloads, so that typos in client requests surface as errors instead of being silently ignored.
+//
+// Parameters:
+//   - jsonData: []byte - The JSON data to be unmarshaled.
+//   - target: interface{} - A pointer to the struct into which the JSON data will be unmarshaled.
+//
+// Returns:
+//   - error: An error if the JSON data is invalid, holds an unknown field or trailing data. Otherwise, returns nil.
+func ReadJSONBStrict(jsonData []byte, target interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return fmt.Errorf("unexpected data after JSON value")
+	}
+	return nil
+}
+
+// ReadJSONBTolerant reads JSON data into the target interface, ignoring a leading UTF-8 byte-order mark.
+//
+// This function behaves like ReadJSONB, but strips the BOM that some editors prepend to UTF-8 files and that would otherwise make the unmarshaling fail.
+//
+// Parameters:
+//   - jsonData: []byte - The JSON data to be unmarshaled, possibly starting with a BOM.
+//   - target: interface{} - A pointer to the type into which the JSON data will be unmarshaled.
+//
+// Returns:
+//   - error: An error if the unmarshaling process fails. Otherwise, returns nil.
+func ReadJSONBTolerant(jsonData []byte, target interface{}) error {
+	return ReadJSONB(StripBOM(jsonData), target)
+}
+
 // NewJSONB creates a new JSONB instance from the provided data.
 //
 // This function marshals the input 'data' into JSON format and then unmarshals it into a map[string]interface{}. It returns the created JSONB instance and any error encountered during the process.
@@ -295,6 +507,29 @@ func NewJSONB(data interface{}) (JSONB, error) {
 	return JSONB(dataMap), nil
 }
 
+// NewJSONBFromReader creates a new JSONB instance by decoding a JSON object from a reader.
+//
+// This function decodes directly from the reader, avoiding to load the whole payload into a byte slice first.
+//
+// Parameters:
+//   - r: io.Reader - The reader holding the JSON object.
+//
+// Returns:
+//   - JSONB: The created JSONB instance.
+//   - error: An error if the decoding process fails or if the top-level JSON value is not an object.
+func NewJSONBFromReader(r io.Reader) (JSONB, error) {
+	var data interface{}
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+
+	dataMap, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a JSON object, got %s", jsonTypeName(data))
+	}
+	return JSONB(dataMap), nil
+}
+
 // MarshalJSONB marshals a JSONB instance into JSON format.
 //
 // This function takes a JSONB instance as input and marshals it into JSON format. It returns the JSON representation of the input data and any error encountered during the marshaling process.
@@ -321,6 +556,35 @@ func MarshalJSONB(data JSONB) ([]byte, error) {
 	return json.Marshal(data)
 }
 
+// MarshalJSONBCanonical marshals a JSONB instance into canonical JSON format.
+//
+// This function produces compact JSON with sorted keys at every level, including the keys of structs nested in the document, which encoding/json would otherwise emit in field order. The output is therefore suitable for hashing or signing: logically equal documents give byte-identical output.
+//
+// Parameters:
+//   - data: JSONB - The JSONB instance to marshal into canonical JSON format.
+//
+// Returns:
+//   - []byte: The canonical JSON representation of the input JSONB instance.
+//   - error: An error if the marshaling process fails.
+//
+// Note:
+//   - Numbers are kept as they are first encoded, so 1 and 1.0 are both rendered as 1.
+func MarshalJSONBCanonical(data JSONB) ([]byte, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(generic)
+}
+
 // MarshalJSONBA marshals the provided JSONBA slice into JSON format.
 //
 // This function takes a JSONBA slice, which is essentially a slice of map[string]interface{}, and marshals it into JSON format using the encoding/json package.
@@ -359,6 +623,29 @@ func NewJSONBA(data interface{}) (JSONBA, error) {
 	return JSONBA(dataMap), nil
 }
 
+// StructsToJSONBA converts a slice of structs into a JSONBA instance.
+//
+// This function is the bulk complement of NewJSONB: every element is marshaled into JSON format, honoring its json tags, and unmarshaled into a map[string]interface{}.
+//
+// Parameters:
+//   - items: []T - The structs to be converted into JSONBA.
+//
+// Returns:
+//   - JSONBA: The created JSONBA instance, holding one map per element in the same order.
+//   - error: An error if the marshaling or unmarshaling process fails.
+//
+// Example:
+//
+//	people := []Person{{Name: "John", Age: 30}, {Name: "Jane", Age: 25}}
+//	jsonba, err := StructsToJSONBA(people)
+//	if err != nil {
+//	    fmt.Println("Error:", err)
+//	    return
+//	}
+func StructsToJSONBA[T any](items []T) (JSONBA, error) {
+	return NewJSONBA(items)
+}
+
 // UnmarshalJSON unmarshals JSON data into the target interface{}.
 //
 // This function takes JSON data as input and unmarshals it into the provided target interface{}. If the input data is already a []byte, it directly unmarshals it; otherwise, it marshals the data into []byte first. It returns any error encountered during the unmarshaling process.
@@ -459,3 +746,1513 @@ func (j *JSONBA) Scan(value interface{}) error {
 
 	return nil
 }
+
+// DigJSON safely retrieves a nested value from decoded JSON data.
+//
+// This function descends through nested map[string]interface{} (or JSONB) values following the given keys, and returns the value found at the end of the path. It never panics on unexpected types.
+//
+// Parameters:
+//   - v: interface{} - The decoded JSON data to descend into.
+//   - path: ...string - The keys to follow, from the outermost to the innermost.
+//
+// Returns:
+//   - interface{}: The value found at the end of the path, or nil if it was not found.
+//   - bool: true if the path exists, false if a key is missing or a value along the path is not a map.
+//
+// Example:
+//
+//	var data interface{}
+//	_ = json.Unmarshal([]byte(`{"user": {"address": {"city": "Bangkok"}}}`), &data)
+//	city, ok := DigJSON(data, "user", "address", "city")
+//
+// This will return "Bangkok" and true.
+func DigJSON(v interface{}, path ...string) (interface{}, bool) {
+	current := v
+	for _, key := range path {
+		var m map[string]interface{}
+		switch node := current.(type) {
+		case map[string]interface{}:
+			m = node
+		case JSONB:
+			m = node
+		default:
+			return nil, false
+		}
+
+		next, ok := m[key]
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+
+	return current, true
+}
+
+// DecodeArrayLenient decodes a JSON array element by element, isolating errors per element.
+//
+// This function splits the JSON array into its raw elements and decodes each of them into a fresh value created by 'newElem'. Elements that fail to decode (e.g. because of a type mismatch) are skipped and their error is collected, so that a single bad element does not fail the whole batch.
+//
+// Parameters:
+//   - data: []byte - The JSON array to decode.
+//   - newElem: func() interface{} - A function returning a new pointer to decode each element into.
+//
+// Returns:
+//   - []interface{}: The successfully decoded elements, in their original order.
+//   - []error: One error per element that failed to decode, mentioning its index. If 'data' is not a valid JSON array, a single error is returned and no element is decoded.
+//
+// Example:
+//
+//	results, errs := DecodeArrayLenient(jsonData, func() interface{} { return &User{} })
+//	for _, err := range errs {
+//	    fmt.Println("Skipped:", err)
+//	}
+//
+// Note:
+//   - The array itself must be syntactically valid JSON, since it is split before its elements are decoded.
+func DecodeArrayLenient(data []byte, newElem func() interface{}) (results []interface{}, errs []error) {
+	var rawElems []json.RawMessage
+	if err := json.Unmarshal(data, &rawElems); err != nil {
+		return nil, []error{err}
+	}
+
+	for i, raw := range rawElems {
+		elem := newElem()
+		if err := json.Unmarshal(raw, elem); err != nil {
+			errs = append(errs, fmt.Errorf("element %d: %w", i, err))
+			continue
+		}
+		results = append(results, elem)
+	}
+
+	return results, errs
+}
+
+// MapsToStructs converts a slice of maps, such as database rows, into a slice of typed structs.
+//
+// This function marshals each map as a JSONB value and unmarshals it into a new 'T', so the keys of the maps are matched against the json tags of the struct fields (e.g. snake_case column names).
+//
+// Parameters:
+//   - rows: []map[string]interface{} - The maps to convert.
+//
+// Returns:
+//   - []T: The converted structs, in the same order as the input rows.
+//   - error: An error mentioning the row index if any of the rows fails to convert.
+//
+// Example:
+//
+//	type User struct {
+//	    ID        int    `json:"id"`
+//	    FirstName string `json:"first_name"`
+//	}
+//
+//	users, err := MapsToStructs[User](rows)
+//	if err != nil {
+//	    fmt.Println("Error:", err)
+//	    return
+//	}
+func MapsToStructs[T any](rows []map[string]interface{}) ([]T, error) {
+	result := make([]T, len(rows))
+	for i, row := range rows {
+		if err := UnmarshalJSON(JSONB(row), &result[i]); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+	}
+
+	return result, nil
+}
+
+// MergeJSONBLayers deep-merges JSONB documents, later layers taking precedence over earlier ones.
+//
+// This function is meant for layered configurations (e.g. defaults < file < environment). Nested objects are merged key by key, any other value from a later layer replaces the earlier one, and nil values mean "no override". The input layers are left untouched.
+//
+// Parameters:
+//   - layers: ...JSONB - The documents to merge, from the lowest to the highest precedence.
+//
+// Returns:
+//   - JSONB: A new JSONB document holding the merged values.
+//
+// Example:
+//
+//	defaults := JSONB{"db": map[string]interface{}{"host": "localhost", "port": 5432}}
+//	env := JSONB{"db": map[string]interface{}{"host": "db.internal"}}
+//	config := MergeJSONBLayers(defaults, env)
+//
+// This will return {"db": {"host": "db.internal", "port": 5432}}.
+func MergeJSONBLayers(layers ...JSONB) JSONB {
+	merged := JSONB{}
+	for _, layer := range layers {
+		mergeJSONBInto(merged, layer)
+	}
+	return merged
+}
+
+// mergeJSONBInto deep-merges src into dst, skipping nil values of src.
+func mergeJSONBInto(dst, src map[string]interface{}) {
+	for key, value := range src {
+		if value == nil {
+			continue
+		}
+
+		srcMap, srcIsMap := asJSONObject(value)
+		if !srcIsMap {
+			dst[key] = value
+			continue
+		}
+
+		dstMap, dstIsMap := asJSONObject(dst[key])
+		merged := make(map[string]interface{}, len(srcMap))
+		if dstIsMap {
+			mergeJSONBInto(merged, dstMap)
+		}
+		mergeJSONBInto(merged, srcMap)
+		dst[key] = merged
+	}
+}
+
+// asJSONObject returns value as a map if it is a JSON object (map[string]interface{} or JSONB).
+func asJSONObject(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case JSONB:
+		return v, true
+	default:
+		return nil, false
+	}
+}
+
+// ToEnvFile renders the JSONB value as the content of a flat "KEY=value" env file.
+//
+// Nested objects are flattened by joining their keys with underscores, and every name is uppercased with any character other than letters and digits replaced by an underscore (e.g. {"db": {"host": "x"}} gives "DB_HOST=x"). Lines are sorted by name so the output is deterministic.
+//
+// Parameters:
+//   - prefix: string - An optional prefix prepended to every name (e.g. "APP" gives "APP_DB_HOST"). Pass an empty string for no prefix.
+//
+// Returns:
+//   - string: The env file content, one "NAME=value" line per scalar value.
+//
+// Note:
+//   - Arrays are not representable as a single env value and are skipped.
+//   - nil values are rendered as an empty value.
+//   - Values containing whitespace, quotes, "#" or "=" are double-quoted with Go escaping.
+func (j JSONB) ToEnvFile(prefix string) string {
+	lines := []string{}
+	flattenJSONBToEnv(j, envName(prefix), &lines)
+	sort.Strings(lines)
+
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// flattenJSONBToEnv appends a "NAME=value" line for every scalar value of m to lines.
+func flattenJSONBToEnv(m map[string]interface{}, prefix string, lines *[]string) {
+	for key, value := range m {
+		name := envName(key)
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		if nested, ok := asJSONObject(value); ok {
+			flattenJSONBToEnv(nested, name, lines)
+			continue
+		}
+
+		var str string
+		switch v := value.(type) {
+		case nil:
+		case string:
+			str = v
+		case float64:
+			str = FloatToString(v)
+		default:
+			if IsSlice(v) {
+				continue
+			}
+			str = fmt.Sprint(v)
+		}
+		if strings.ContainsAny(str, " \t\r\n\"'#=") {
+			str = strconv.Quote(str)
+		}
+		*lines = append(*lines, name+"="+str)
+	}
+}
+
+// envName converts a key into an uppercase env variable name.
+func envName(key string) string {
+	return strings.Trim(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, key), "_")
+}
+
+// JSONBFromEnvFile parses the content of a "KEY=value" env file into a nested JSONB value.
+//
+// Blank lines and lines starting with "#" are ignored. Values may be double-quoted (with Go escaping, as produced by ToEnvFile) or single-quoted (taken literally). Names are lowercased and split on underscores to rebuild nested objects, so "DB_HOST=x" gives {"db": {"host": "x"}}. This is the counterpart of ToEnvFile.
+//
+// Parameters:
+//   - data: []byte - The env file content.
+//
+// Returns:
+//   - JSONB: The parsed document. All values are strings.
+//   - error: An error mentioning the line number if a line is malformed, or if a name is used both as a value and as a parent of other names (e.g. "DB=x" and "DB_HOST=y").
+//
+// Note:
+//   - Since underscores are always treated as separators, keys originally containing underscores cannot be told apart from nested keys.
+func JSONBFromEnvFile(data []byte) (JSONB, error) {
+	result := JSONB{}
+	for i, line := range strings.Split(string(StripBOM(data)), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=value", i+1)
+		}
+
+		value = strings.TrimSpace(value)
+		switch {
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			value = unquoted
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		}
+
+		keys := strings.Split(strings.ToLower(name), "_")
+		node := map[string]interface{}(result)
+		for _, key := range keys[:len(keys)-1] {
+			child, exists := node[key]
+			if !exists {
+				child = map[string]interface{}{}
+				node[key] = child
+			}
+			childMap, ok := child.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("line %d: %s conflicts with a previous value", i+1, name)
+			}
+			node = childMap
+		}
+
+		last := keys[len(keys)-1]
+		if _, isMap := node[last].(map[string]interface{}); isMap {
+			return nil, fmt.Errorf("line %d: %s conflicts with a previous value", i+1, name)
+		}
+		node[last] = value
+	}
+
+	return result, nil
+}
+
+// AllowOnly checks that the JSONB value only contains the allowed top-level keys.
+//
+// This method is useful for strict APIs that must reject unexpected fields.
+//
+// Parameters:
+//   - keys: ...string - The allowed top-level keys.
+//
+// Returns:
+//   - error: An error naming every disallowed key (sorted), or nil if the document conforms.
+//
+// Example:
+//
+//	payload := JSONB{"name": "John", "role": "admin"}
+//	err := payload.AllowOnly("name", "email")
+//
+// This will return an error mentioning "role".
+func (j JSONB) AllowOnly(keys ...string) error {
+	var disallowed []string
+	for key := range j {
+		if !Contains(keys, key) {
+			disallowed = append(disallowed, key)
+		}
+	}
+
+	if len(disallowed) > 0 {
+		sort.Strings(disallowed)
+		return fmt.Errorf("disallowed keys: %s", strings.Join(disallowed, ", "))
+	}
+	return nil
+}
+
+// DiffSummary counts the top-level keys added, removed and changed between two JSONB documents.
+//
+// Values are compared with reflect.DeepEqual, so a nested object counts as a single changed key whatever the number of differences it holds.
+//
+// Parameters:
+//   - oldData: JSONB - The previous version of the document.
+//   - newData: JSONB - The current version of the document.
+//
+// Returns:
+//   - added: int - The number of keys only present in newData.
+//   - removed: int - The number of keys only present in oldData.
+//   - changed: int - The number of keys present in both with different values.
+func DiffSummary(oldData, newData JSONB) (added, removed, changed int) {
+	for key, oldValue := range oldData {
+		newValue, ok := newData[key]
+		if !ok {
+			removed++
+		} else if !reflect.DeepEqual(oldValue, newValue) {
+			changed++
+		}
+	}
+	for key := range newData {
+		if _, ok := oldData[key]; !ok {
+			added++
+		}
+	}
+
+	return added, removed, changed
+}
+
+// TypeChanges reports the top-level keys whose JSON type changed between two JSONB documents, e.g. to monitor schema drift.
+//
+// Keys only present in one of the documents are not reported, neither are keys whose value changed but kept the same type.
+//
+// Parameters:
+//   - oldData: JSONB - The previous version of the document.
+//   - newData: JSONB - The current version of the document.
+//
+// Returns:
+//   - map[string][2]string: The old and new type names ("null", "string", "boolean", "number", "object" or "array") by key.
+//
+// Example:
+//
+//	changes := TypeChanges(JSONB{"id": 42}, JSONB{"id": "42"})
+//
+// This will return map[string][2]string{"id": {"number", "string"}}.
+func TypeChanges(oldData, newData JSONB) map[string][2]string {
+	changes := make(map[string][2]string)
+	for key, oldValue := range oldData {
+		newValue, ok := newData[key]
+		if !ok {
+			continue
+		}
+		if oldType, newType := jsonTypeName(oldValue), jsonTypeName(newValue); oldType != newType {
+			changes[key] = [2]string{oldType, newType}
+		}
+	}
+
+	return changes
+}
+
+// SerializationDelta compares the serialized sizes of two JSONB documents, e.g. to estimate the storage cost of an update.
+//
+// Both documents are marshaled with MarshalJSONB, so a nil document counts as the 4 bytes of "null".
+//
+// Parameters:
+//   - oldData: JSONB - The previous version of the document.
+//   - newData: JSONB - The current version of the document.
+//
+// Returns:
+//   - oldSize: int - The size in bytes of the serialized oldData.
+//   - newSize: int - The size in bytes of the serialized newData.
+//   - delta: int - newSize minus oldSize, negative when the document shrank.
+//   - err: error - An error if any of the documents cannot be marshaled.
+func SerializationDelta(oldData, newData JSONB) (oldSize, newSize, delta int, err error) {
+	oldBytes, err := MarshalJSONB(oldData)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	newBytes, err := MarshalJSONB(newData)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	return len(oldBytes), len(newBytes), len(newBytes) - len(oldBytes), nil
+}
+
+// UnmarshalArrayField decodes an array field of a JSONB value into a typed slice.
+//
+// Parameters:
+//   - j: JSONB - The document holding the array field.
+//   - key: string - The top-level key of the array field.
+//
+// Returns:
+//   - []T: The decoded elements of the array.
+//   - error: An error if the key is absent, its value is not an array, or an element cannot be decoded into T.
+//
+// Example:
+//
+//	order := JSONB{"items": []interface{}{map[string]interface{}{"sku": "A1", "qty": 2}}}
+//	items, err := UnmarshalArrayField[OrderItem](order, "items")
+//	if err != nil {
+//	    fmt.Println("Error:", err)
+//	    return
+//	}
+func UnmarshalArrayField[T any](j JSONB, key string) ([]T, error) {
+	value, ok := j[key]
+	if !ok {
+		return nil, fmt.Errorf("key %q not found", key)
+	}
+	if !IsSlice(value) {
+		return nil, fmt.Errorf("key %q is not an array", key)
+	}
+
+	var result []T
+	if err := UnmarshalJSON(value, &result); err != nil {
+		return nil, fmt.Errorf("key %q: %w", key, err)
+	}
+	return result, nil
+}
+
+// CacheKey builds a deterministic cache key from mixed arguments.
+//
+// Every argument is serialized to JSON, which sorts map keys, so equal maps give the same key whatever their insertion order. Scalars keep their type in the serialization, so 1 and "1" give different keys. The serialized arguments are then hashed with SHA-256.
+//
+// Parameters:
+//   - parts: ...interface{} - The arguments identifying the cached value.
+//
+// Returns:
+//   - string: The hex-encoded SHA-256 hash of the serialized arguments.
+//
+// Example:
+//
+//	key := CacheKey("users", 42, map[string]interface{}{"active": true, "role": "admin"})
+//
+// Note:
+//   - Arguments that cannot be serialized to JSON (e.g. functions or channels) fall back to their fmt representation.
+func CacheKey(parts ...interface{}) string {
+	h := sha256.New()
+	for _, part := range parts {
+		data, err := json.Marshal(part)
+		if err != nil {
+			data = []byte(fmt.Sprintf("%T:%v", part, part))
+		}
+		h.Write(data)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ValidatePaths checks the types of nested values of the JSONB value.
+//
+// Each rule maps a dot-separated path (e.g. "user.age") to the expected JSON type of its value: "string", "number", "boolean" (or "bool"), "object", "array" or "null". All the rules are checked, and every violation is reported.
+//
+// Parameters:
+//   - rules: map[string]string - The expected JSON type of each path.
+//
+// Returns:
+//   - error: An error joining one error per missing path or type mismatch (sorted by path), or nil if the document conforms.
+//
+// Example:
+//
+//	payload := JSONB{"user": map[string]interface{}{"name": "John", "age": "30"}}
+//	err := payload.ValidatePaths(map[string]string{"user.name": "string", "user.age": "number"})
+//
+// This will return an error mentioning that "user.age" is a string instead of a number.
+func (j JSONB) ValidatePaths(rules map[string]string) error {
+	paths := make([]string, 0, len(rules))
+	for path := range rules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var errs []error
+	for _, path := range paths {
+		expected := rules[path]
+		if expected == "bool" {
+			expected = "boolean"
+		}
+
+		value, ok := j.GetPath(path)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: missing", path))
+			continue
+		}
+		if actual := jsonTypeName(value); actual != expected {
+			errs = append(errs, fmt.Errorf("%s: expected %s, got %s", path, expected, actual))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// jsonTypeName returns the JSON type name of a decoded value.
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, json.Number:
+		return "number"
+	case map[string]interface{}, JSONB:
+		return "object"
+	default:
+		if IsSlice(value) {
+			return "array"
+		}
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// GetPath retrieves a nested value of the JSONB value by its dot-separated path (e.g. "user.address.city").
+//
+// Parameters:
+//   - path: string - The dot-separated path of the value.
+//
+// Returns:
+//   - interface{}: The value found at the path, or nil if it was not found.
+//   - bool: true if the path exists, false otherwise.
+func (j JSONB) GetPath(path string) (interface{}, bool) {
+	return DigJSON(j, strings.Split(path, ".")...)
+}
+
+// SetPath sets a nested value of the JSONB value by its dot-separated path (e.g. "user.address.city"), creating the missing intermediate objects.
+//
+// Parameters:
+//   - path: string - The dot-separated path of the value.
+//   - value: interface{} - The value to set.
+//
+// Returns:
+//   - error: An error if the JSONB value is nil or a segment of the path holds a value that is not an object (e.g. "user.name.first" when "user.name" is a string).
+func (j JSONB) SetPath(path string, value interface{}) error {
+	if j == nil {
+		return fmt.Errorf("cannot set %q on a nil JSONB", path)
+	}
+
+	keys := strings.Split(path, ".")
+	current := map[string]interface{}(j)
+	for i, key := range keys[:len(keys)-1] {
+		next, ok := current[key]
+		if !ok {
+			child := make(map[string]interface{})
+			current[key] = child
+			current = child
+			continue
+		}
+
+		child, ok := asJSONObject(next)
+		if !ok {
+			return fmt.Errorf("cannot set %q: %q holds a %s, not an object", path, strings.Join(keys[:i+1], "."), jsonTypeName(next))
+		}
+		current = child
+	}
+
+	current[keys[len(keys)-1]] = value
+	return nil
+}
+
+// RemovePath deletes a nested value of the JSONB value by its dot-separated path (e.g. "user.address.city").
+// Removing an object removes all the values it holds.
+//
+// Parameters:
+//   - path: string - The dot-separated path of the value.
+//
+// Returns:
+//   - bool: true if a value was removed, false if the path did not exist.
+func (j JSONB) RemovePath(path string) bool {
+	keys := strings.Split(path, ".")
+	parent, ok := DigJSON(j, keys[:len(keys)-1]...)
+	if !ok {
+		return false
+	}
+	m, ok := asJSONObject(parent)
+	if !ok {
+		return false
+	}
+
+	last := keys[len(keys)-1]
+	if _, ok := m[last]; !ok {
+		return false
+	}
+	delete(m, last)
+	return true
+}
+
+// ValidateRequired checks that the JSONB value contains all the required top-level keys.
+//
+// Parameters:
+//   - keys: ...string - The required top-level keys.
+//
+// Returns:
+//   - error: An error naming every missing key, or nil if all of them are present.
+func (j JSONB) ValidateRequired(keys ...string) error {
+	var missing []string
+	for _, key := range keys {
+		if _, ok := j[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required keys: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// ValidateRequiredPaths checks that the JSONB value contains all the required dot-separated paths.
+//
+// Parameters:
+//   - paths: ...string - The required paths, e.g. "user.address.city".
+//
+// Returns:
+//   - error: An error naming every missing path, or nil if all of them are present.
+func (j JSONB) ValidateRequiredPaths(paths ...string) error {
+	var missing []string
+	for _, path := range paths {
+		if _, ok := j.GetPath(path); !ok {
+			missing = append(missing, path)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required paths: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// CumulativeSum computes the running total of a numeric field over the JSONBA elements.
+//
+// Parameters:
+//   - key: string - The key of the numeric field in every element.
+//
+// Returns:
+//   - []float64: The running total after each element, in slice order.
+//   - error: An error mentioning the element index if a value is missing or not numeric.
+//
+// Example:
+//
+//	sales := JSONBA{{"amount": 10}, {"amount": 5}, {"amount": 2.5}}
+//	totals, err := sales.CumulativeSum("amount")
+//
+// This will return [10 15 17.5].
+func (a JSONBA) CumulativeSum(key string) ([]float64, error) {
+	totals := make([]float64, len(a))
+	var total float64
+	for i, item := range a {
+		value, err := numericField(item, key)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		total += value
+		totals[i] = total
+	}
+	return totals, nil
+}
+
+// numericField returns the value of key in item as a float64.
+func numericField(item map[string]interface{}, key string) (float64, error) {
+	value, ok := item[key]
+	if !ok {
+		return 0, fmt.Errorf("key %q not found", key)
+	}
+	number, ok := toFloat64(value)
+	if !ok {
+		return 0, fmt.Errorf("key %q is not numeric: %v", key, value)
+	}
+	return number, nil
+}
+
+// toFloat64 converts a decoded numeric value into a float64.
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case nil, string, bool:
+		return 0, false
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// TopN returns the first n elements of the JSONBA sorted by a numeric field.
+//
+// The sort is stable, so elements with equal values keep their original order. Elements whose value is missing or not numeric are placed last. The JSONBA itself is left untouched.
+//
+// Parameters:
+//   - key: string - The key of the numeric field to sort by.
+//   - n: int - The maximum number of elements to return. All the elements are returned when n is larger than the JSONBA.
+//   - descending: bool - Whether to sort from the largest to the smallest value.
+//
+// Returns:
+//   - JSONBA: The top n elements.
+//
+// Example:
+//
+//	players := JSONBA{{"name": "a", "score": 10}, {"name": "b", "score": 30}, {"name": "c", "score": 20}}
+//	podium := players.TopN("score", 2, true)
+//
+// This will return the elements of "b" and "c".
+func (a JSONBA) TopN(key string, n int, descending bool) JSONBA {
+	if n <= 0 {
+		return JSONBA{}
+	}
+
+	sorted := make(JSONBA, len(a))
+	copy(sorted, a)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		vi, errI := numericField(sorted[i], key)
+		vj, errJ := numericField(sorted[j], key)
+		if errI != nil || errJ != nil {
+			return errI == nil && errJ != nil
+		}
+		if descending {
+			return vi > vj
+		}
+		return vi < vj
+	})
+
+	if n > len(sorted) {
+		n = len(sorted)
+	}
+	return sorted[:n]
+}
+
+// Hash computes a stable fingerprint of the JSONB value.
+//
+// The document is serialized with MarshalJSONBCanonical, so two documents equal ignoring key order give the same hash.
+//
+// Returns:
+//   - string: The hex-encoded SHA-256 hash of the canonical JSON representation.
+//   - error: An error if the document cannot be marshaled.
+func (j JSONB) Hash() (string, error) {
+	data, err := MarshalJSONBCanonical(j)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// DecodeJSONB decodes a JSON value from a reader into the target interface.
+//
+// This function behaves like ReadJSONB, but decodes directly from the reader instead of requiring the whole payload to be loaded into a byte slice first.
+//
+// Parameters:
+//   - r: io.Reader - The reader holding the JSON data.
+//   - target: interface{} - A pointer to the type into which the JSON data will be decoded.
+//
+// Returns:
+//   - error: An error if the decoding process fails. Otherwise, returns nil.
+func DecodeJSONB(r io.Reader, target interface{}) error {
+	return json.NewDecoder(r).Decode(target)
+}
+
+// DecodeJSONBArray streams JSON objects from a reader, calling fn with each of them in turn.
+//
+// The reader may hold either a JSON array of objects, or a stream of consecutive objects (such as newline-delimited JSON). Only one element is held in memory at a time, which makes this function suitable for big export files.
+//
+// Parameters:
+//   - r: io.Reader - The reader holding the JSON data.
+//   - fn: func(JSONB) error - The function called with every decoded object. Returning an error stops the decoding.
+//
+// Returns:
+//   - error: The error returned by fn, or an error if the decoding process fails. Otherwise, returns nil.
+//
+// Example:
+//
+//	file, _ := os.Open("export.json")
+//	defer file.Close()
+//	err := DecodeJSONBArray(file, func(item JSONB) error {
+//	    fmt.Println(item["id"])
+//	    return nil
+//	})
+func DecodeJSONBArray(r io.Reader, fn func(JSONB) error) error {
+	reader := bufio.NewReader(r)
+	isArray := false
+	for {
+		b, err := reader.Peek(1)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if b[0] == ' ' || b[0] == '\t' || b[0] == '\r' || b[0] == '\n' {
+			_, _ = reader.ReadByte()
+			continue
+		}
+		isArray = b[0] == '['
+		break
+	}
+
+	decoder := json.NewDecoder(reader)
+	if isArray {
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+	}
+
+	for {
+		if isArray && !decoder.More() {
+			_, err := decoder.Token()
+			return err
+		}
+
+		var item JSONB
+		if err := decoder.Decode(&item); err != nil {
+			if !isArray && err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+}
+
+// ToSQLSet builds the SET clause of an SQL UPDATE statement from the top-level keys of the JSONB value.
+//
+// Columns are sorted by name so the clause is deterministic, and the values are returned as arguments to bind to the placeholders.
+//
+// Parameters:
+//   - style: string - The placeholder style: "postgres" (or "$") for numbered "$1" placeholders, anything else for "?" placeholders.
+//   - startIndex: int - The number of the first placeholder in the "postgres" style, so that the clause can be composed with other arguments (e.g. those of a WHERE clause). It is ignored with "?" placeholders.
+//
+// Returns:
+//   - setClause: string - The clause, e.g. `"email" = $1, "name" = $2`.
+//   - args: []interface{} - The values to bind, in placeholder order.
+//
+// Example:
+//
+//	changes := JSONB{"name": "John", "email": "john@example.com"}
+//	set, args := changes.ToSQLSet("postgres", 2)
+//	db.Exec("UPDATE users SET "+set+" WHERE id = $1", append([]interface{}{userID}, args...)...)
+//
+// Note:
+//   - Keys are quoted as identifiers, with double quotes in the "postgres" style and backticks (MySQL) otherwise, any embedded quote being doubled. A hostile key can therefore not inject SQL, but it should still be checked against the allowed columns (e.g. with AllowOnly), since any existing column could be updated.
+func (j JSONB) ToSQLSet(style string, startIndex int) (setClause string, args []interface{}) {
+	keys := j.Keys()
+	assignments := make([]string, len(keys))
+	args = make([]interface{}, len(keys))
+	postgres := style == "postgres" || style == "$"
+	for i, key := range keys {
+		column, placeholder := "`"+strings.ReplaceAll(key, "`", "``")+"`", "?"
+		if postgres {
+			column = `"` + strings.ReplaceAll(key, `"`, `""`) + `"`
+			placeholder = "$" + strconv.Itoa(startIndex+i)
+		}
+		assignments[i] = column + " = " + placeholder
+		args[i] = j[key]
+	}
+
+	return strings.Join(assignments, ", "), args
+}
+
+// WeightedAvg computes the weighted average of a numeric field over the JSONBA elements.
+//
+// Parameters:
+//   - valueKey: string - The key of the numeric field to average.
+//   - weightKey: string - The key of the numeric field holding the weight of each element.
+//
+// Returns:
+//   - float64: The weighted average, or 0 when the total weight is 0 (including an empty JSONBA).
+//   - error: An error mentioning the element index if a value or weight is missing or not numeric.
+//
+// Example:
+//
+//	grades := JSONBA{{"grade": 4, "credits": 3}, {"grade": 2, "credits": 1}}
+//	gpa, err := grades.WeightedAvg("grade", "credits")
+//
+// This will return 3.5.
+func (a JSONBA) WeightedAvg(valueKey, weightKey string) (float64, error) {
+	var sum, totalWeight float64
+	for i, item := range a {
+		value, err := numericField(item, valueKey)
+		if err != nil {
+			return 0, fmt.Errorf("element %d: %w", i, err)
+		}
+		weight, err := numericField(item, weightKey)
+		if err != nil {
+			return 0, fmt.Errorf("element %d: %w", i, err)
+		}
+		sum += value * weight
+		totalWeight += weight
+	}
+
+	if totalWeight == 0 {
+		return 0, nil
+	}
+	return sum / totalWeight, nil
+}
+
+// AuditDiff computes the fields that changed between two versions of a data structure.
+//
+// Both inputs are converted with ConvertToJSONB, then every top-level key whose value differs is reported, unless it is listed in 'ignore' (e.g. "updated_at" or "password"). A key missing from one of the versions is reported with a nil value on that side.
+//
+// Parameters:
+//   - oldData: interface{} - The previous version of the data structure.
+//   - newData: interface{} - The current version of the data structure.
+//   - ignore: []string - The keys never to report.
+//
+// Returns:
+//   - JSONB: A JSONB mapping each changed key to {"old": ..., "new": ...}, ready to be stored in an audit-log table. It is empty when nothing changed.
+//   - error: An error if any of the inputs cannot be converted into JSONB.
+//
+// Example:
+//
+//	diff, err := AuditDiff(oldUser, newUser, []string{"updated_at", "password"})
+//	if err != nil {
+//	    fmt.Println("Error:", err)
+//	    return
+//	}
+//
+// With only the email changed, 'diff' will contain {"email": {"old": "john@example.com", "new": "john@example.org"}}.
+func AuditDiff(oldData, newData interface{}, ignore []string) (JSONB, error) {
+	oldJSONB, newJSONB, err := ConvertToJSONB(oldData, newData)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := JSONB{}
+	for _, key := range changedKeys(oldJSONB, newJSONB) {
+		if !Contains(ignore, key) {
+			diff[key] = map[string]interface{}{"old": oldJSONB[key], "new": newJSONB[key]}
+		}
+	}
+
+	return diff, nil
+}
+
+// ToHTMLTable renders the JSONB value as a simple two-column key/value HTML table, for debugging views.
+//
+// Rows are sorted by key and every key and value is HTML-escaped. Nested objects found at the top level are rendered as nested tables, while deeper objects and arrays are rendered as their JSON representation.
+//
+// Returns:
+//   - string: The HTML table.
+//
+// Example:
+//
+//	html := JSONB{"name": "<John>"}.ToHTMLTable()
+//
+// This will return "<table><tr><th>name</th><td>&lt;John&gt;</td></tr></table>".
+func (j JSONB) ToHTMLTable() string {
+	var sb strings.Builder
+	writeHTMLTable(&sb, j, 1)
+	return sb.String()
+}
+
+// writeHTMLTable writes m as an HTML table, rendering nested objects as tables up to the given depth.
+func writeHTMLTable(sb *strings.Builder, m map[string]interface{}, depth int) {
+	sb.WriteString("<table>")
+	for _, key := range JSONB(m).Keys() {
+		sb.WriteString("<tr><th>")
+		sb.WriteString(html.EscapeString(key))
+		sb.WriteString("</th><td>")
+
+		value := m[key]
+		if nested, ok := asJSONObject(value); ok && depth > 0 {
+			writeHTMLTable(sb, nested, depth-1)
+		} else if str, ok := value.(string); ok {
+			sb.WriteString(html.EscapeString(str))
+		} else {
+			data, err := json.Marshal(value)
+			if err != nil {
+				data = []byte(fmt.Sprint(value))
+			}
+			sb.WriteString(html.EscapeString(string(data)))
+		}
+
+		sb.WriteString("</td></tr>")
+	}
+	sb.WriteString("</table>")
+}
+
+// StructPatch builds a partial update payload holding only the fields that changed between two versions of a struct.
+//
+// Both inputs are converted with ConvertToJSONB, so the fields are keyed by their json tag. A field present in oldObj but missing from newObj (e.g. emptied with omitempty) is set to nil, following the JSON merge patch convention.
+//
+// Parameters:
+//   - oldObj: interface{} - The previous version of the struct.
+//   - newObj: interface{} - The current version of the struct.
+//
+// Returns:
+//   - JSONB: The new values of the changed fields. It is empty when nothing changed.
+//   - error: An error if any of the inputs cannot be converted into JSONB.
+//
+// Example:
+//
+//	patch, err := StructPatch(oldUser, newUser)
+//	if err != nil {
+//	    fmt.Println("Error:", err)
+//	    return
+//	}
+//
+// With only the email changed, 'patch' will contain {"email": "john@example.org"}.
+func StructPatch(oldObj, newObj interface{}) (JSONB, error) {
+	oldJSONB, newJSONB, err := ConvertToJSONB(oldObj, newObj)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := JSONB{}
+	for _, key := range changedKeys(oldJSONB, newJSONB) {
+		patch[key] = newJSONB[key]
+	}
+
+	return patch, nil
+}
+
+// HasDrifted reports whether a data structure no longer matches a JSONB snapshot of it.
+//
+// The current value is converted with NewJSONB and compared key by key with the snapshot, which is typically used for optimistic-concurrency checks.
+//
+// Parameters:
+//   - current: interface{} - The current data structure.
+//   - snapshot: JSONB - The snapshot taken earlier.
+//
+// Returns:
+//   - bool: true if any top-level key was added, removed or changed.
+//   - []string: The sorted list of the keys that differ.
+//   - error: An error if the current value cannot be converted into JSONB.
+func HasDrifted(current interface{}, snapshot JSONB) (bool, []string, error) {
+	currentJSONB, err := NewJSONB(current)
+	if err != nil {
+		return false, nil, err
+	}
+
+	changed := changedKeys(snapshot, currentJSONB)
+	return len(changed) > 0, changed, nil
+}
+
+// changedKeys returns the sorted top-level keys whose values differ between two JSONB documents, including keys present in only one of them.
+func changedKeys(oldJSONB, newJSONB JSONB) []string {
+	changed := []string{}
+	for key, oldValue := range oldJSONB {
+		if newValue, ok := newJSONB[key]; !ok || !reflect.DeepEqual(oldValue, newValue) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range newJSONB {
+		if _, ok := oldJSONB[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// CoerceNumericStrings returns a copy of the JSONB value where the strings holding a valid JSON number (e.g. "42", "-1.5", "1e3")
+// are replaced by their float64 value, at every level including inside arrays.
+//
+// Strings that do not fully parse as a number, such as "42 apples", " 42" or "0x2A", are left intact.
+//
+// Returns:
+//   - JSONB: The coerced copy. The original value is left untouched.
+//
+// Example:
+//
+//	j := JSONB{"id": "42", "name": "John", "scores": []interface{}{"1.5", "n/a"}}
+//	coerced := j.CoerceNumericStrings()
+//
+// This will return JSONB{"id": 42.0, "name": "John", "scores": []interface{}{1.5, "n/a"}}.
+func (j JSONB) CoerceNumericStrings() JSONB {
+	if j == nil {
+		return nil
+	}
+	return JSONB(coerceNumericStringsMap(j))
+}
+
+// coerceNumericStringsMap returns a copy of m with its numeric strings coerced to float64.
+func coerceNumericStringsMap(m map[string]interface{}) map[string]interface{} {
+	coerced := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		coerced[key] = coerceNumericStringsValue(value)
+	}
+	return coerced
+}
+
+// coerceNumericStringsValue coerces value if it is a numeric string, recursing into objects and arrays.
+func coerceNumericStringsValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if isJSONNumber(v) {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f
+			}
+		}
+		return v
+	case map[string]interface{}:
+		return coerceNumericStringsMap(v)
+	case JSONB:
+		return JSONB(coerceNumericStringsMap(v))
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = coerceNumericStringsValue(item)
+		}
+		return items
+	case []map[string]interface{}:
+		items := make([]map[string]interface{}, len(v))
+		for i, item := range v {
+			items[i] = coerceNumericStringsMap(item)
+		}
+		return items
+	case JSONBA:
+		items := make(JSONBA, len(v))
+		for i, item := range v {
+			items[i] = coerceNumericStringsMap(item)
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+// isJSONNumber reports whether s is exactly a number literal of the JSON grammar.
+func isJSONNumber(s string) bool {
+	if s == "" || (s[0] != '-' && (s[0] < '0' || s[0] > '9')) || strings.TrimSpace(s) != s {
+		return false
+	}
+	return json.Valid([]byte(s))
+}
+
+// Clone returns a deep copy of the JSONB value, so that mutating the copy at any level leaves the original untouched.
+//
+// The copy is made by recursion rather than by a marshal/unmarshal round-trip, so the values keep their Go types (an int stays an int instead of becoming a float64).
+// Maps, slices and arrays of any type are copied at every level, while other values such as pointers are shared with the original.
+//
+// Returns:
+//   - JSONB: The deep copy, or nil if the JSONB value is nil.
+func (j JSONB) Clone() JSONB {
+	if j == nil {
+		return nil
+	}
+	return JSONB(cloneJSONMap(j))
+}
+
+// cloneJSONMap returns a deep copy of m.
+func cloneJSONMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+
+	cloned := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		cloned[key] = cloneJSONValue(value)
+	}
+	return cloned
+}
+
+// cloneJSONValue returns a deep copy of the maps and slices held by value.
+func cloneJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return cloneJSONMap(v)
+	case JSONB:
+		return v.Clone()
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = cloneJSONValue(item)
+		}
+		return items
+	case []map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		items := make([]map[string]interface{}, len(v))
+		for i, item := range v {
+			items[i] = cloneJSONMap(item)
+		}
+		return items
+	case JSONBA:
+		if v == nil {
+			return v
+		}
+		items := make(JSONBA, len(v))
+		for i, item := range v {
+			items[i] = cloneJSONMap(item)
+		}
+		return items
+	default:
+		return cloneReflectValue(value)
+	}
+}
+
+// cloneReflectValue deep copies the slices, arrays and maps of any other type (e.g. []JSONB or map[string]string), cloning their elements with cloneJSONValue.
+func cloneReflectValue(value interface{}) interface{} {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice:
+		if rv.IsNil() {
+			return value
+		}
+		items := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			items.Index(i).Set(clonedElem(rv.Index(i), rv.Type().Elem()))
+		}
+		return items.Interface()
+	case reflect.Array:
+		items := reflect.New(rv.Type()).Elem()
+		for i := 0; i < rv.Len(); i++ {
+			items.Index(i).Set(clonedElem(rv.Index(i), rv.Type().Elem()))
+		}
+		return items.Interface()
+	case reflect.Map:
+		if rv.IsNil() {
+			return value
+		}
+		cloned := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			cloned.SetMapIndex(iter.Key(), clonedElem(iter.Value(), rv.Type().Elem()))
+		}
+		return cloned.Interface()
+	default:
+		return value
+	}
+}
+
+// clonedElem returns a deep copy of an element of a slice, array or map, as a value assignable to elemType.
+func clonedElem(elem reflect.Value, elemType reflect.Type) reflect.Value {
+	cloned := reflect.ValueOf(cloneJSONValue(elem.Interface()))
+	if !cloned.IsValid() {
+		return reflect.Zero(elemType)
+	}
+	return cloned
+}
+
+// ApplyMaskPolicy returns a copy of the JSONB value where the values matched by path globs are replaced by a mask, e.g. before logging a document holding personal data.
+//
+// Each key of the policy is a dot-separated path whose segments are matched with path.Match, so "*" matches any key or any array index at its level (e.g. "cards.*.cvv").
+// Paths that match nothing are ignored. When several globs match the same value, they are applied in lexical order, so the mask of the last glob wins.
+//
+// Parameters:
+//   - policy: map[string]string - The mask to apply by path glob.
+//
+// Returns:
+//   - JSONB: The masked copy. The original value is left untouched.
+//
+// Example:
+//
+//	j := JSONB{"user": JSONB{"ssn": "123-45-6789"}, "cards": []interface{}{JSONB{"number": "4242", "cvv": "123"}}}
+//	masked := j.ApplyMaskPolicy(map[string]string{"user.ssn": "***-**-****", "cards.*.cvv": "***"})
+//
+// This will return JSONB{"user": JSONB{"ssn": "***-**-****"}, "cards": []interface{}{JSONB{"number": "4242", "cvv": "***"}}}.
+func (j JSONB) ApplyMaskPolicy(policy map[string]string) JSONB {
+	masked := j.Clone()
+
+	globs := make([]string, 0, len(policy))
+	for glob := range policy {
+		globs = append(globs, glob)
+	}
+	sort.Strings(globs)
+
+	for _, glob := range globs {
+		maskMatches(map[string]interface{}(masked), strings.Split(glob, "."), policy[glob])
+	}
+	return masked
+}
+
+// Redact returns a copy of the JSONB value where the values of the given keys are replaced by "***", e.g. before logging a request body.
+//
+// A key without dots is redacted at any depth, including inside the objects held by arrays, so "password" also masks {"user": {"password": ...}}.
+// A dotted key is a path from the root (e.g. "card.number") applied with ApplyMaskPolicy.
+// In both cases the keys are matched literally, their glob metacharacters being escaped (e.g. "a*b" does not mask "ab").
+//
+// Parameters:
+//   - keys: ...string - The keys or paths of the values to redact (e.g. "password" or "card.number").
+//
+// Returns:
+//   - JSONB: The redacted copy. The original value is left untouched.
+//
+// Example:
+//
+//	body := JSONB{"email": "john@example.com", "password": "secret", "card": JSONB{"number": "4242424242424242"}}
+//	log.Println(body.Redact("password", "card.number"))
+func (j JSONB) Redact(keys ...string) JSONB {
+	policy := make(map[string]string, len(keys))
+	var anywhere []string
+	for _, key := range keys {
+		if strings.Contains(key, ".") {
+			policy[globEscaper.Replace(key)] = "***"
+		} else {
+			anywhere = append(anywhere, key)
+		}
+	}
+
+	redacted := j.ApplyMaskPolicy(policy)
+	for _, key := range anywhere {
+		redactKeyAnywhere(redacted, key)
+	}
+	return redacted
+}
+
+// redactKeyAnywhere replaces by "***" the values of key in every object held by value, at any depth.
+// The objects are modified in place, so value must be a copy.
+func redactKeyAnywhere(value interface{}, key string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			if k == key {
+				v[k] = "***"
+			} else {
+				redactKeyAnywhere(item, key)
+			}
+		}
+	case JSONB:
+		redactKeyAnywhere(map[string]interface{}(v), key)
+	case []interface{}:
+		for _, item := range v {
+			redactKeyAnywhere(item, key)
+		}
+	case []map[string]interface{}:
+		for _, item := range v {
+			redactKeyAnywhere(item, key)
+		}
+	case JSONBA:
+		for _, item := range v {
+			redactKeyAnywhere(item, key)
+		}
+	default:
+		redactReflectValue(reflect.ValueOf(value), key)
+	}
+}
+
+// redactReflectValue applies redactKeyAnywhere to the elements of the slices, arrays and maps of any other type (e.g. []JSONB or map[string]string).
+func redactReflectValue(rv reflect.Value, key string) {
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			redactKeyAnywhere(rv.Index(i).Interface(), key)
+		}
+	case reflect.Map:
+		mask := reflect.ValueOf("***")
+		iter := rv.MapRange()
+		for iter.Next() {
+			k := iter.Key()
+			if k.Kind() == reflect.String && k.String() == key && mask.Type().AssignableTo(rv.Type().Elem()) {
+				rv.SetMapIndex(k, mask)
+			} else {
+				redactKeyAnywhere(iter.Value().Interface(), key)
+			}
+		}
+	}
+}
+
+// globEscaper escapes the metacharacters of path.Match patterns.
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`)
+
+// maskMatches replaces by mask the values of the maps and slices held by value whose path matches the remaining segments.
+func maskMatches(value interface{}, segments []string, mask string) {
+	last := len(segments) == 1
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if matched, _ := path.Match(segments[0], key); !matched {
+				continue
+			}
+			if last {
+				v[key] = mask
+			} else {
+				maskMatches(item, segments[1:], mask)
+			}
+		}
+	case JSONB:
+		maskMatches(map[string]interface{}(v), segments, mask)
+	case []interface{}:
+		for i, item := range v {
+			if matched, _ := path.Match(segments[0], strconv.Itoa(i)); !matched {
+				continue
+			}
+			if last {
+				v[i] = mask
+			} else {
+				maskMatches(item, segments[1:], mask)
+			}
+		}
+	case []map[string]interface{}:
+		maskMapSlice(v, segments, mask)
+	case JSONBA:
+		maskMapSlice(v, segments, mask)
+	}
+}
+
+// maskMapSlice applies maskMatches to the matching elements of a slice of maps. The elements themselves cannot be replaced by a mask.
+func maskMapSlice(items []map[string]interface{}, segments []string, mask string) {
+	if len(segments) == 1 {
+		return
+	}
+	for i, item := range items {
+		if matched, _ := path.Match(segments[0], strconv.Itoa(i)); matched {
+			maskMatches(item, segments[1:], mask)
+		}
+	}
+}
+
+// CSVToJSONBA parses CSV data into a JSONBA, the first row holding the headers used as keys for the following rows.
+//
+// The data is read with encoding/csv, so quoted fields may contain commas, quotes and line breaks. All the values are kept as strings, and a leading UTF-8 BOM is ignored.
+// Rows shorter than the header row get an empty string for their missing columns, while rows longer than the header row are rejected since their extra values have no key.
+//
+// Parameters:
+//   - data: []byte - The CSV data.
+//
+// Returns:
+//   - JSONBA: One map per data row. It is empty, not nil, for an empty input or an input holding only the header row.
+//   - error: An error if the CSV is malformed or a row has more fields than the header row.
+//
+// Example:
+//
+//	rows, err := CSVToJSONBA([]byte("name,city\nJohn,\"Bangkok, Thailand\"\n"))
+//	if err != nil {
+//	    fmt.Println("Error:", err)
+//	    return
+//	}
+//
+// This will return JSONBA{{"name": "John", "city": "Bangkok, Thailand"}}.
+func CSVToJSONBA(data []byte) (JSONBA, error) {
+	reader := csv.NewReader(bytes.NewReader(StripBOM(data)))
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return JSONBA{}, nil
+	}
+
+	headers := records[0]
+	rows := make(JSONBA, 0, len(records)-1)
+	for i, record := range records[1:] {
+		if len(record) > len(headers) {
+			return nil, fmt.Errorf("row %d has %d fields, but there are only %d headers", i+1, len(record), len(headers))
+		}
+
+		row := make(map[string]interface{}, len(headers))
+		for col, header := range headers {
+			if col < len(record) {
+				row[header] = record[col]
+			} else {
+				row[header] = ""
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
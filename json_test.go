@@ -0,0 +1,1204 @@
+package goease
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONBEntries(t *testing.T) {
+	jsonData := JSONB{"name": "John", "age": 30, "city": "New York"}
+
+	entries := jsonData.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries got %d", len(entries))
+	}
+
+	expected := []string{"age", "city", "name"}
+	for i, entry := range entries {
+		if entry.Key != expected[i] {
+			t.Errorf("expected key %q at index %d got %q", expected[i], i, entry.Key)
+		}
+		if entry.Value != jsonData[entry.Key] {
+			t.Errorf("expected value %v for key %q got %v", jsonData[entry.Key], entry.Key, entry.Value)
+		}
+	}
+}
+
+func TestDigJSON(t *testing.T) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(`{"user": {"address": {"city": "Bangkok"}, "name": "John"}}`), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	city, ok := DigJSON(data, "user", "address", "city")
+	if !ok || city != "Bangkok" {
+		t.Errorf("expected Bangkok got %v (found %v)", city, ok)
+	}
+
+	if _, ok := DigJSON(data, "user", "phone"); ok {
+		t.Error("expected missing key to not be found")
+	}
+
+	if _, ok := DigJSON(data, "user", "name", "first"); ok {
+		t.Error("expected non-map mid-path to not be found")
+	}
+}
+
+func TestDecodeArrayLenient(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	data := []byte(`[{"name": "John", "age": 30}, {"name": "Jane", "age": "unknown"}, {"name": "Bob", "age": 25}]`)
+	results, errs := DecodeArrayLenient(data, func() interface{} { return &user{} })
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results got %d", len(results))
+	}
+	if results[0].(*user).Name != "John" || results[1].(*user).Name != "Bob" {
+		t.Errorf("unexpected results %v %v", results[0], results[1])
+	}
+
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error got %d", len(errs))
+	}
+	if !strings.Contains(errs[0].Error(), "element 1") {
+		t.Errorf("expected error to mention element index got %q", errs[0])
+	}
+
+	if _, errs := DecodeArrayLenient([]byte(`{"name": "John"}`), func() interface{} { return &user{} }); len(errs) != 1 {
+		t.Errorf("expected a single error for non array input got %v", errs)
+	}
+}
+
+func TestMapsToStructs(t *testing.T) {
+	type user struct {
+		ID        int    `json:"id"`
+		FirstName string `json:"first_name"`
+	}
+
+	rows := []map[string]interface{}{
+		{"id": 1, "first_name": "John"},
+		{"id": 2, "first_name": "Jane", "last_name": "Doe"},
+	}
+
+	users, err := MapsToStructs[user](rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []user{{ID: 1, FirstName: "John"}, {ID: 2, FirstName: "Jane"}}
+	if !reflect.DeepEqual(users, expected) {
+		t.Errorf("expected %v got %v", expected, users)
+	}
+
+	if _, err := MapsToStructs[user]([]map[string]interface{}{{"id": "one"}}); err == nil {
+		t.Error("expected error for mismatched type")
+	}
+}
+
+func TestMergeJSONBLayers(t *testing.T) {
+	defaults := JSONB{
+		"debug": false,
+		"db":    map[string]interface{}{"host": "localhost", "port": 5432, "ssl": false},
+		"name":  "app",
+	}
+	file := JSONB{
+		"db":   JSONB{"host": "db.local", "ssl": true},
+		"name": nil,
+	}
+	env := JSONB{
+		"debug": true,
+		"db":    map[string]interface{}{"host": "db.internal", "port": nil},
+	}
+
+	expected := JSONB{
+		"debug": true,
+		"db":    map[string]interface{}{"host": "db.internal", "port": 5432, "ssl": true},
+		"name":  "app",
+	}
+
+	if got := MergeJSONBLayers(defaults, file, env); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v got %v", expected, got)
+	}
+
+	if defaults["db"].(map[string]interface{})["host"] != "localhost" {
+		t.Error("expected input layers to be left untouched")
+	}
+}
+
+func TestReadJSONBTolerant(t *testing.T) {
+	withBOM := append([]byte{0xEF, 0xBB, 0xBF}, []byte(`{"name":"John"}`)...)
+
+	var target JSONB
+	if err := ReadJSONB(withBOM, &target); err == nil {
+		t.Error("expected ReadJSONB to fail on a BOM")
+	}
+
+	target = nil
+	if err := ReadJSONBTolerant(withBOM, &target); err != nil {
+		t.Fatal(err)
+	}
+	if target["name"] != "John" {
+		t.Errorf("unexpected result %v", target)
+	}
+
+	target = nil
+	if err := ReadJSONBTolerant([]byte(`{"name":"Jane"}`), &target); err != nil {
+		t.Fatal(err)
+	}
+	if target["name"] != "Jane" {
+		t.Errorf("unexpected result %v", target)
+	}
+}
+
+func TestJSONBToEnvFile(t *testing.T) {
+	config := JSONB{
+		"debug": true,
+		"db": map[string]interface{}{
+			"host": "localhost",
+			"port": float64(5432),
+			"pool": JSONB{"max-open": 10},
+		},
+		"motd":  "hello world",
+		"hosts": []interface{}{"a", "b"},
+		"empty": nil,
+	}
+
+	expected := "APP_DB_HOST=localhost\n" +
+		"APP_DB_POOL_MAX_OPEN=10\n" +
+		"APP_DB_PORT=5432\n" +
+		"APP_DEBUG=true\n" +
+		"APP_EMPTY=\n" +
+		"APP_MOTD=\"hello world\"\n"
+	if got := config.ToEnvFile("app"); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	if got := (JSONB{"name": "x"}).ToEnvFile(""); got != "NAME=x\n" {
+		t.Errorf("unexpected output without prefix %q", got)
+	}
+}
+
+func TestJSONBFromEnvFile(t *testing.T) {
+	data := []byte(`# database settings
+DB_HOST=localhost
+DB_PORT=5432
+
+  # pool
+DB_POOL_MAX=10
+MOTD="hello \"world\""
+GREETING='it is # not a comment'
+DEBUG=
+`)
+
+	expected := JSONB{
+		"db": map[string]interface{}{
+			"host": "localhost",
+			"port": "5432",
+			"pool": map[string]interface{}{"max": "10"},
+		},
+		"motd":     `hello "world"`,
+		"greeting": "it is # not a comment",
+		"debug":    "",
+	}
+
+	got, err := JSONBFromEnvFile(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v got %v", expected, got)
+	}
+
+	if _, err := JSONBFromEnvFile([]byte("NOT A PAIR")); err == nil {
+		t.Error("expected error for malformed line")
+	}
+	if _, err := JSONBFromEnvFile([]byte("DB=x\nDB_HOST=y")); err == nil {
+		t.Error("expected error for conflicting names")
+	}
+}
+
+func TestJSONBAllowOnly(t *testing.T) {
+	if err := (JSONB{"name": "John", "email": "john@example.com"}).AllowOnly("name", "email", "age"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	err := (JSONB{"name": "John", "role": "admin", "admin": true}).AllowOnly("name", "email")
+	if err == nil {
+		t.Fatal("expected error for disallowed keys")
+	}
+	if !strings.Contains(err.Error(), "admin, role") {
+		t.Errorf("expected error to name the disallowed keys got %q", err)
+	}
+}
+
+func TestDiffSummary(t *testing.T) {
+	oldData := JSONB{
+		"name":    "John",
+		"age":     30,
+		"city":    "Bangkok",
+		"tags":    []interface{}{"a"},
+		"address": map[string]interface{}{"zip": "10110"},
+	}
+	newData := JSONB{
+		"name":    "John",
+		"age":     31,
+		"tags":    []interface{}{"a", "b"},
+		"address": map[string]interface{}{"zip": "10110"},
+		"email":   "john@example.com",
+		"phone":   "0123",
+	}
+
+	added, removed, changed := DiffSummary(oldData, newData)
+	if added != 2 || removed != 1 || changed != 2 {
+		t.Errorf("expected 2 added, 1 removed, 2 changed got %d, %d, %d", added, removed, changed)
+	}
+
+	if added, removed, changed := DiffSummary(nil, nil); added+removed+changed != 0 {
+		t.Error("expected no difference between empty documents")
+	}
+}
+
+func TestTypeChanges(t *testing.T) {
+	oldData := JSONB{"id": float64(42), "name": "John", "tags": []interface{}{"a"}, "removed": true}
+	newData := JSONB{"id": "42", "name": "Jane", "tags": nil, "added": 1}
+
+	expected := map[string][2]string{
+		"id":   {"number", "string"},
+		"tags": {"array", "null"},
+	}
+	if got := TypeChanges(oldData, newData); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v got %v", expected, got)
+	}
+}
+
+func TestSerializationDelta(t *testing.T) {
+	small := JSONB{"a": 1}
+	large := JSONB{"a": 1, "name": "John"}
+
+	oldSize, newSize, delta, err := SerializationDelta(small, large)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// {"a":1} and {"a":1,"name":"John"}
+	if oldSize != 7 || newSize != 21 || delta != 14 {
+		t.Errorf("expected 7, 21, 14 got %d, %d, %d", oldSize, newSize, delta)
+	}
+
+	if _, _, delta, err := SerializationDelta(large, small); err != nil || delta != -14 {
+		t.Errorf("expected a negative delta got %d, %v", delta, err)
+	}
+	if _, _, _, err := SerializationDelta(small, JSONB{"ch": make(chan int)}); err == nil {
+		t.Error("expected error for unmarshalable document")
+	}
+}
+
+func TestUnmarshalArrayField(t *testing.T) {
+	type item struct {
+		SKU string `json:"sku"`
+		Qty int    `json:"qty"`
+	}
+
+	order := JSONB{
+		"id": "order-1",
+		"items": []interface{}{
+			map[string]interface{}{"sku": "A1", "qty": 2},
+			map[string]interface{}{"sku": "B2", "qty": 1},
+		},
+	}
+
+	items, err := UnmarshalArrayField[item](order, "items")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []item{{"A1", 2}, {"B2", 1}}; !reflect.DeepEqual(items, expected) {
+		t.Errorf("expected %v got %v", expected, items)
+	}
+
+	if _, err := UnmarshalArrayField[item](order, "lines"); err == nil {
+		t.Error("expected error for missing key")
+	}
+	if _, err := UnmarshalArrayField[item](order, "id"); err == nil {
+		t.Error("expected error for non array value")
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	first := map[string]interface{}{}
+	first["role"] = "admin"
+	first["active"] = true
+	first["filters"] = JSONB{"b": 2, "a": 1}
+
+	second := map[string]interface{}{}
+	second["filters"] = JSONB{"a": 1, "b": 2}
+	second["active"] = true
+	second["role"] = "admin"
+
+	key := CacheKey("users", 42, first)
+	if key != CacheKey("users", 42, second) {
+		t.Error("expected equal arguments to give the same key")
+	}
+	if len(key) != 64 {
+		t.Errorf("expected a hex sha256 key got %q", key)
+	}
+
+	if CacheKey("users", 42) == CacheKey("users", "42") {
+		t.Error("expected scalars of different types to give different keys")
+	}
+	if CacheKey("ab", "c") == CacheKey("a", "bc") {
+		t.Error("expected argument boundaries to be part of the key")
+	}
+}
+
+func TestJSONBValidatePaths(t *testing.T) {
+	var payload JSONB
+	if err := json.Unmarshal([]byte(`{"user": {"name": "John", "age": 30, "admin": false, "tags": ["a"], "address": {"city": "Bangkok"}}}`), &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	err := payload.ValidatePaths(map[string]string{
+		"user":              "object",
+		"user.name":         "string",
+		"user.age":          "number",
+		"user.admin":        "bool",
+		"user.tags":         "array",
+		"user.address.city": "string",
+	})
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	err = payload.ValidatePaths(map[string]string{
+		"user.name":         "number",
+		"user.address.city": "object",
+		"user.address.zip":  "string",
+		"user.email":        "string",
+	})
+	if err == nil {
+		t.Fatal("expected error for invalid paths")
+	}
+	for _, expected := range []string{
+		"user.name: expected number, got string",
+		"user.address.city: expected object, got string",
+		"user.address.zip: missing",
+		"user.email: missing",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error to contain %q got %q", expected, err)
+		}
+	}
+}
+
+func TestJSONBValidateRequired(t *testing.T) {
+	payload := JSONB{
+		"name": "John",
+		"user": map[string]interface{}{"address": map[string]interface{}{"city": "Bangkok"}},
+	}
+
+	if err := payload.ValidateRequired("name", "user"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := payload.ValidateRequired("name", "email"); err == nil || !strings.Contains(err.Error(), "email") {
+		t.Errorf("expected error naming email got %v", err)
+	}
+
+	if err := payload.ValidateRequiredPaths("name", "user.address.city"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := payload.ValidateRequiredPaths("user.address.city", "user.address.zip"); err == nil || !strings.Contains(err.Error(), "user.address.zip") {
+		t.Errorf("expected error naming user.address.zip got %v", err)
+	}
+}
+
+func TestJSONBKeysAndValues(t *testing.T) {
+	jsonData := JSONB{"name": "John", "age": 30, "city": "New York"}
+
+	keys := jsonData.Keys()
+	if expected := []string{"age", "city", "name"}; !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected %v got %v", expected, keys)
+	}
+
+	values := jsonData.Values()
+	if len(values) != len(keys) {
+		t.Fatalf("expected %d values got %d", len(keys), len(values))
+	}
+	for i, key := range keys {
+		if values[i] != jsonData[key] {
+			t.Errorf("expected value %v at index %d got %v", jsonData[key], i, values[i])
+		}
+	}
+
+	if len(JSONB{}.Keys()) != 0 || len(JSONB(nil).Values()) != 0 {
+		t.Error("expected no keys nor values for an empty document")
+	}
+}
+
+func TestStructsToJSONBA(t *testing.T) {
+	type person struct {
+		FirstName string `json:"first_name"`
+		Age       int    `json:"age"`
+		Password  string `json:"-"`
+	}
+
+	result, err := StructsToJSONBA([]person{{"John", 30, "secret"}, {"Jane", 25, "secret"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := JSONBA{
+		{"first_name": "John", "age": float64(30)},
+		{"first_name": "Jane", "age": float64(25)},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %v got %v", expected, result)
+	}
+}
+
+func TestJSONBACumulativeSum(t *testing.T) {
+	sales := JSONBA{{"amount": 10}, {"amount": float64(5)}, {"amount": 2.5}, {"amount": json.Number("1.5")}}
+
+	totals, err := sales.CumulativeSum("amount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []float64{10, 15, 17.5, 19}; !reflect.DeepEqual(totals, expected) {
+		t.Errorf("expected %v got %v", expected, totals)
+	}
+
+	if _, err := (JSONBA{{"amount": 10}, {"amount": "5"}}).CumulativeSum("amount"); err == nil || !strings.Contains(err.Error(), "element 1") {
+		t.Errorf("expected error for non numeric value got %v", err)
+	}
+	if _, err := (JSONBA{{"total": 10}}).CumulativeSum("amount"); err == nil {
+		t.Error("expected error for missing value")
+	}
+}
+
+func TestMarshalJSONBCanonical(t *testing.T) {
+	type address struct {
+		Zip  string `json:"zip"`
+		City string `json:"city"`
+	}
+
+	first := JSONB{}
+	first["name"] = "John"
+	first["address"] = address{Zip: "10110", City: "Bangkok"}
+	first["meta"] = map[string]interface{}{"b": 2, "a": []interface{}{1, "x"}}
+
+	second := JSONB{}
+	second["meta"] = map[string]interface{}{"a": []interface{}{1, "x"}, "b": 2}
+	second["address"] = map[string]interface{}{"city": "Bangkok", "zip": "10110"}
+	second["name"] = "John"
+
+	firstJSON, err := MarshalJSONBCanonical(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondJSON, err := MarshalJSONBCanonical(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(firstJSON, secondJSON) {
+		t.Errorf("expected byte-identical output got %s and %s", firstJSON, secondJSON)
+	}
+	if expected := `{"address":{"city":"Bangkok","zip":"10110"},"meta":{"a":[1,"x"],"b":2},"name":"John"}`; string(firstJSON) != expected {
+		t.Errorf("expected %s got %s", expected, firstJSON)
+	}
+}
+
+func TestJSONBATopN(t *testing.T) {
+	players := JSONBA{
+		{"name": "a", "score": 10},
+		{"name": "b", "score": 30},
+		{"name": "c", "score": "n/a"},
+		{"name": "d", "score": 20},
+		{"name": "e", "score": 40},
+	}
+	names := func(a JSONBA) []interface{} {
+		result := []interface{}{}
+		for _, item := range a {
+			result = append(result, item["name"])
+		}
+		return result
+	}
+
+	if got := names(players.TopN("score", 3, true)); !reflect.DeepEqual(got, []interface{}{"e", "b", "d"}) {
+		t.Errorf("unexpected top 3 descending %v", got)
+	}
+	if got := names(players.TopN("score", 10, false)); !reflect.DeepEqual(got, []interface{}{"a", "d", "b", "e", "c"}) {
+		t.Errorf("unexpected result for n larger than the slice %v", got)
+	}
+	if players[0]["name"] != "a" || players[1]["name"] != "b" {
+		t.Error("expected input to be left untouched")
+	}
+	if got := players.TopN("score", 0, true); len(got) != 0 {
+		t.Errorf("expected no element for n = 0 got %v", got)
+	}
+}
+
+func TestJSONBHash(t *testing.T) {
+	first := JSONB{}
+	first["name"] = "John"
+	first["meta"] = map[string]interface{}{"b": 2, "a": 1}
+
+	second := JSONB{}
+	second["meta"] = JSONB{"a": 1, "b": 2}
+	second["name"] = "John"
+
+	firstHash, err := first.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondHash, err := second.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if firstHash != secondHash {
+		t.Errorf("expected equal documents to have the same hash got %s and %s", firstHash, secondHash)
+	}
+
+	second["name"] = "Jane"
+	changedHash, err := second.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changedHash == firstHash {
+		t.Error("expected a changed value to change the hash")
+	}
+
+	if _, err := (JSONB{"fn": func() {}}).Hash(); err == nil {
+		t.Error("expected error for a document that cannot be marshaled")
+	}
+}
+
+func TestReadJSONBStrict(t *testing.T) {
+	type payload struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+
+	var target payload
+	if err := ReadJSONBStrict([]byte(`{"name": "John", "email": "john@example.com"}`), &target); err != nil {
+		t.Fatal(err)
+	}
+	if target.Name != "John" || target.Email != "john@example.com" {
+		t.Errorf("unexpected result %+v", target)
+	}
+
+	withTypo := []byte(`{"name": "John", "emial": "john@example.com"}`)
+	if err := ReadJSONBStrict(withTypo, &payload{}); err == nil {
+		t.Error("expected error for unknown field in strict mode")
+	}
+	if err := ReadJSONB(withTypo, &payload{}); err != nil {
+		t.Errorf("expected no error in lenient mode got %v", err)
+	}
+
+	if err := ReadJSONBStrict([]byte(`{"name": "John"} {"name": "Jane"}`), &payload{}); err == nil {
+		t.Error("expected error for trailing data")
+	}
+}
+
+func TestDecodeJSONB(t *testing.T) {
+	var target JSONB
+	if err := DecodeJSONB(strings.NewReader(`{"name": "John", "age": 30}`), &target); err != nil {
+		t.Fatal(err)
+	}
+	if target["name"] != "John" || target["age"] != float64(30) {
+		t.Errorf("unexpected result %v", target)
+	}
+
+	if err := DecodeJSONB(strings.NewReader(`{"name": `), &target); err == nil {
+		t.Error("expected error for truncated input")
+	}
+}
+
+func TestDecodeJSONBArray(t *testing.T) {
+	collect := func(input string) ([]interface{}, error) {
+		var ids []interface{}
+		err := DecodeJSONBArray(strings.NewReader(input), func(item JSONB) error {
+			ids = append(ids, item["id"])
+			return nil
+		})
+		return ids, err
+	}
+	expected := []interface{}{float64(1), float64(2), float64(3)}
+
+	ids, err := collect(` [{"id": 1}, {"id": 2}, {"id": 3}] `)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected %v got %v", expected, ids)
+	}
+
+	ids, err = collect("{\"id\": 1}\n{\"id\": 2}\n{\"id\": 3}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected %v got %v", expected, ids)
+	}
+
+	if _, err := collect(`[{"id": 1}, "oops"]`); err == nil {
+		t.Error("expected error for non object element")
+	}
+
+	errStop := errors.New("stop")
+	calls := 0
+	err = DecodeJSONBArray(strings.NewReader(`[{"id": 1}, {"id": 2}]`), func(item JSONB) error {
+		calls++
+		return errStop
+	})
+	if err != errStop || calls != 1 {
+		t.Errorf("expected decoding to stop on callback error got %v after %d calls", err, calls)
+	}
+}
+
+func TestNewJSONBFromReader(t *testing.T) {
+	jsonb, err := NewJSONBFromReader(strings.NewReader(`{"name": "John", "tags": ["a"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jsonb["name"] != "John" {
+		t.Errorf("unexpected result %v", jsonb)
+	}
+
+	_, err = NewJSONBFromReader(strings.NewReader(`[{"name": "John"}]`))
+	if err == nil || !strings.Contains(err.Error(), "got array") {
+		t.Errorf("expected error for top-level array got %v", err)
+	}
+}
+
+func TestJSONBToSQLSet(t *testing.T) {
+	changes := JSONB{"name": "John", "email": "john@example.com", "age": 30}
+
+	set, args := changes.ToSQLSet("postgres", 1)
+	if set != `"age" = $1, "email" = $2, "name" = $3` {
+		t.Errorf("unexpected set clause %q", set)
+	}
+	if expected := []interface{}{30, "john@example.com", "John"}; !reflect.DeepEqual(args, expected) {
+		t.Errorf("expected %v got %v", expected, args)
+	}
+
+	set, _ = changes.ToSQLSet("postgres", 3)
+	if set != `"age" = $3, "email" = $4, "name" = $5` {
+		t.Errorf("unexpected set clause %q", set)
+	}
+
+	set, args = changes.ToSQLSet("mysql", 3)
+	if set != "`age` = ?, `email` = ?, `name` = ?" {
+		t.Errorf("unexpected set clause %q", set)
+	}
+	if len(args) != 3 {
+		t.Errorf("expected 3 args got %d", len(args))
+	}
+
+	hostile := JSONB{`x" = 1, "admin" = true --`: "v", "y` = 1, `admin` = true --": "w"}
+	set, args = hostile.ToSQLSet("postgres", 1)
+	if set != `"x"" = 1, ""admin"" = true --" = $1, "y`+"`"+` = 1, `+"`"+`admin`+"`"+` = true --" = $2` {
+		t.Errorf("expected hostile keys to be quoted got %q", set)
+	}
+	if len(args) != 2 {
+		t.Errorf("expected 2 args got %d", len(args))
+	}
+	set, _ = hostile.ToSQLSet("?", 1)
+	if set != "`x\" = 1, \"admin\" = true --` = ?, `y`` = 1, ``admin`` = true --` = ?" {
+		t.Errorf("expected hostile keys to be quoted got %q", set)
+	}
+}
+
+func TestJSONBAWeightedAvg(t *testing.T) {
+	grades := JSONBA{{"grade": 4, "credits": 3}, {"grade": 2.0, "credits": float64(1)}}
+	avg, err := grades.WeightedAvg("grade", "credits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if avg != 3.5 {
+		t.Errorf("expected 3.5 got %v", avg)
+	}
+
+	avg, err = (JSONBA{{"grade": 4, "credits": 0}, {"grade": 2, "credits": 0}}).WeightedAvg("grade", "credits")
+	if err != nil || avg != 0 {
+		t.Errorf("expected 0 for zero total weight got %v, %v", avg, err)
+	}
+
+	if _, err := (JSONBA{{"grade": "A", "credits": 3}}).WeightedAvg("grade", "credits"); err == nil {
+		t.Error("expected error for non numeric value")
+	}
+	if _, err := (JSONBA{{"grade": 4}}).WeightedAvg("grade", "credits"); err == nil {
+		t.Error("expected error for missing weight")
+	}
+}
+
+func TestConvertToJSONBA(t *testing.T) {
+	type item struct {
+		SKU string `json:"sku"`
+		Qty int    `json:"qty"`
+	}
+
+	oldItems := []item{{"A1", 1}}
+	newItems := []item{{"A1", 2}, {"B2", 1}}
+
+	oldJSONBA, newJSONBA, err := ConvertToJSONBA(oldItems, newItems)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(oldJSONBA) != 1 || len(newJSONBA) != 2 {
+		t.Fatalf("expected lengths 1 and 2 got %d and %d", len(oldJSONBA), len(newJSONBA))
+	}
+	if newJSONBA[1]["sku"] != "B2" || newJSONBA[0]["qty"] != float64(2) {
+		t.Errorf("unexpected result %v", newJSONBA)
+	}
+
+	if _, _, err := ConvertToJSONB(oldItems, newItems); err == nil {
+		t.Error("expected ConvertToJSONB to fail on slices")
+	}
+	if _, _, err := ConvertToJSONBA(item{"A1", 1}, newItems); err == nil {
+		t.Error("expected error for non slice input")
+	}
+}
+
+func TestAuditDiff(t *testing.T) {
+	type user struct {
+		Name      string `json:"name"`
+		Email     string `json:"email"`
+		Password  string `json:"password"`
+		UpdatedAt string `json:"updated_at"`
+	}
+
+	oldUser := user{"John", "john@example.com", "hash1", "2024-01-01"}
+	newUser := user{"John", "john@example.org", "hash2", "2024-02-01"}
+
+	diff, err := AuditDiff(oldUser, newUser, []string{"updated_at", "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := JSONB{"email": map[string]interface{}{"old": "john@example.com", "new": "john@example.org"}}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("expected %v got %v", expected, diff)
+	}
+
+	diff, err = AuditDiff(map[string]interface{}{"a": 1}, map[string]interface{}{"b": 2}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff) != 2 || diff["a"].(map[string]interface{})["new"] != nil {
+		t.Errorf("expected added and removed keys to be reported got %v", diff)
+	}
+}
+
+func TestJSONBToHTMLTable(t *testing.T) {
+	doc := JSONB{
+		"name":  "<script>alert('x')</script>",
+		"age":   30,
+		"tags":  []interface{}{"a&b"},
+		"owner": map[string]interface{}{"email": "john@example.com", "meta": map[string]interface{}{"x": 1}},
+	}
+
+	expected := "<table>" +
+		"<tr><th>age</th><td>30</td></tr>" +
+		"<tr><th>name</th><td>&lt;script&gt;alert(&#39;x&#39;)&lt;/script&gt;</td></tr>" +
+		"<tr><th>owner</th><td><table>" +
+		"<tr><th>email</th><td>john@example.com</td></tr>" +
+		"<tr><th>meta</th><td>{&#34;x&#34;:1}</td></tr>" +
+		"</table></td></tr>" +
+		"<tr><th>tags</th><td>[&#34;a\\u0026b&#34;]</td></tr>" +
+		"</table>"
+	if got := doc.ToHTMLTable(); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestHasDrifted(t *testing.T) {
+	type account struct {
+		Name    string  `json:"name"`
+		Balance float64 `json:"balance"`
+		Version int     `json:"version"`
+	}
+
+	snapshot, err := NewJSONB(account{"John", 100, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	drifted, changed, err := HasDrifted(account{"John", 100, 1}, snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if drifted || len(changed) != 0 {
+		t.Errorf("expected no drift got %v", changed)
+	}
+
+	drifted, changed, err = HasDrifted(account{"John", 50, 2}, snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !drifted || !reflect.DeepEqual(changed, []string{"balance", "version"}) {
+		t.Errorf("expected balance and version to have drifted got %v", changed)
+	}
+}
+
+func TestStructPatch(t *testing.T) {
+	type user struct {
+		Name  string   `json:"name"`
+		Email string   `json:"email"`
+		Age   int      `json:"age"`
+		Tags  []string `json:"tags,omitempty"`
+	}
+
+	oldUser := user{"John", "john@example.com", 30, []string{"admin"}}
+	newUser := user{"John", "john@example.org", 31, nil}
+
+	patch, err := StructPatch(oldUser, newUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := JSONB{"email": "john@example.org", "age": float64(31), "tags": nil}
+	if !reflect.DeepEqual(patch, expected) {
+		t.Errorf("expected %v got %v", expected, patch)
+	}
+	if _, ok := patch["name"]; ok {
+		t.Error("expected unchanged field to be absent")
+	}
+
+	patch, err = StructPatch(oldUser, oldUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(patch) != 0 {
+		t.Errorf("expected empty patch got %v", patch)
+	}
+}
+
+func TestCoerceNumericStrings(t *testing.T) {
+	j := JSONB{
+		"id":      "42",
+		"ratio":   "-1.5e2",
+		"name":    "John",
+		"partial": "42 apples",
+		"spaced":  " 42",
+		"hex":     "0x2A",
+		"nested":  map[string]interface{}{"zip": "10110"},
+		"scores":  []interface{}{"1.5", "n/a", []interface{}{"7"}},
+	}
+
+	expected := JSONB{
+		"id":      float64(42),
+		"ratio":   float64(-150),
+		"name":    "John",
+		"partial": "42 apples",
+		"spaced":  " 42",
+		"hex":     "0x2A",
+		"nested":  map[string]interface{}{"zip": float64(10110)},
+		"scores":  []interface{}{1.5, "n/a", []interface{}{float64(7)}},
+	}
+	if got := j.CoerceNumericStrings(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v got %v", expected, got)
+	}
+	if j["id"] != "42" || j["scores"].([]interface{})[0] != "1.5" {
+		t.Error("expected original value to be left untouched")
+	}
+}
+
+func TestStructToMapOmitEmpty(t *testing.T) {
+	type profile struct {
+		Bio string `json:"bio"`
+	}
+	type user struct {
+		Name     string            `json:"name"`
+		Nickname string            `json:"nickname,omitempty"`
+		Age      int               `json:"age"`
+		Score    float64           `json:"score"`
+		Profile  *profile          `json:"profile"`
+		Tags     []string          `json:"tags"`
+		Meta     map[string]string `json:"meta"`
+		Password string            `json:"-"`
+		Active   bool
+		internal string
+	}
+
+	u := user{Name: "John", Age: 30, Tags: []string{}, Meta: map[string]string{}, Password: "secret", Active: true, internal: "x"}
+	got, err := StructToMapOmitEmpty(&u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{"name": "John", "age": 30, "Active": true}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v got %v", expected, got)
+	}
+
+	p := &profile{Bio: "gopher"}
+	got, err = StructToMapOmitEmpty(user{Profile: p, Tags: []string{"admin"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got["profile"] != p || !reflect.DeepEqual(got["tags"], []string{"admin"}) {
+		t.Errorf("unexpected result %v", got)
+	}
+
+	if _, err := StructToMapOmitEmpty("not a struct"); err == nil {
+		t.Error("expected error for non struct input")
+	}
+}
+
+func TestJSONBClone(t *testing.T) {
+	original := JSONB{
+		"id":    1,
+		"user":  map[string]interface{}{"name": "John", "address": JSONB{"city": "Bangkok"}},
+		"tags":  []interface{}{"a", map[string]interface{}{"k": "v"}},
+		"codes": []string{"x", "y"},
+		"rows":  JSONBA{{"n": 1}},
+	}
+
+	clone := original.Clone()
+	if !reflect.DeepEqual(clone, original) {
+		t.Fatalf("expected clone to equal original got %v", clone)
+	}
+
+	clone["user"].(map[string]interface{})["name"] = "Jane"
+	clone["user"].(map[string]interface{})["address"].(JSONB)["city"] = "Paris"
+	clone["tags"].([]interface{})[1].(map[string]interface{})["k"] = "changed"
+	clone["codes"].([]string)[0] = "changed"
+	clone["rows"].(JSONBA)[0]["n"] = 2
+
+	user := original["user"].(map[string]interface{})
+	if user["name"] != "John" || user["address"].(JSONB)["city"] != "Bangkok" {
+		t.Errorf("expected nested maps of the original to be untouched got %v", user)
+	}
+	if original["tags"].([]interface{})[1].(map[string]interface{})["k"] != "v" {
+		t.Error("expected maps in slices of the original to be untouched")
+	}
+	if original["codes"].([]string)[0] != "x" || original["rows"].(JSONBA)[0]["n"] != 1 {
+		t.Error("expected typed slices of the original to be untouched")
+	}
+	if _, ok := clone["id"].(int); !ok {
+		t.Error("expected values to keep their type")
+	}
+
+	typed := JSONB{
+		"list":    []JSONB{{"k": "v"}},
+		"matrix":  [][]interface{}{{"a"}},
+		"labels":  []map[string]string{{"k": "v"}},
+		"headers": map[string][]string{"accept": {"json"}},
+		"pair":    [2]map[string]string{{"k": "v"}, nil},
+	}
+	typedClone := typed.Clone()
+	if !reflect.DeepEqual(typedClone, typed) {
+		t.Fatalf("expected clone to equal original got %v", typedClone)
+	}
+	typedClone["list"].([]JSONB)[0]["k"] = "changed"
+	typedClone["matrix"].([][]interface{})[0][0] = "changed"
+	typedClone["labels"].([]map[string]string)[0]["k"] = "changed"
+	typedClone["headers"].(map[string][]string)["accept"][0] = "changed"
+	typedClone["pair"].([2]map[string]string)[0]["k"] = "changed"
+	if typed["list"].([]JSONB)[0]["k"] != "v" ||
+		typed["matrix"].([][]interface{})[0][0] != "a" ||
+		typed["labels"].([]map[string]string)[0]["k"] != "v" ||
+		typed["headers"].(map[string][]string)["accept"][0] != "json" ||
+		typed["pair"].([2]map[string]string)[0]["k"] != "v" {
+		t.Errorf("expected nested values of any type to be deep copied got %v", typed)
+	}
+
+	if JSONB(nil).Clone() != nil {
+		t.Error("expected nil clone of nil JSONB")
+	}
+}
+
+func TestApplyMaskPolicy(t *testing.T) {
+	j := JSONB{
+		"user": map[string]interface{}{"name": "John", "ssn": "123-45-6789"},
+		"cards": []interface{}{
+			map[string]interface{}{"number": "4242424242424242", "cvv": "123"},
+			map[string]interface{}{"number": "5555555555554444", "cvv": "456"},
+		},
+		"rows": JSONBA{{"token": "abc"}},
+	}
+
+	masked := j.ApplyMaskPolicy(map[string]string{
+		"user.ssn":    "***-**-****",
+		"cards.*.cvv": "***",
+		"rows.*.tok*": "[redacted]",
+		"missing.key": "x",
+	})
+
+	expected := JSONB{
+		"user": map[string]interface{}{"name": "John", "ssn": "***-**-****"},
+		"cards": []interface{}{
+			map[string]interface{}{"number": "4242424242424242", "cvv": "***"},
+			map[string]interface{}{"number": "5555555555554444", "cvv": "***"},
+		},
+		"rows": JSONBA{{"token": "[redacted]"}},
+	}
+	if !reflect.DeepEqual(masked, expected) {
+		t.Errorf("expected %v got %v", expected, masked)
+	}
+	if j["user"].(map[string]interface{})["ssn"] != "123-45-6789" {
+		t.Error("expected original value to be left untouched")
+	}
+
+	byIndex := j.ApplyMaskPolicy(map[string]string{"cards.1.number": "****"})
+	cards := byIndex["cards"].([]interface{})
+	if cards[0].(map[string]interface{})["number"] != "4242424242424242" || cards[1].(map[string]interface{})["number"] != "****" {
+		t.Errorf("expected only the second card to be masked got %v", cards)
+	}
+}
+
+func TestJSONBSetPath(t *testing.T) {
+	j := JSONB{"user": JSONB{"name": "John"}}
+
+	if err := j.SetPath("user.address.city", "Bangkok"); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.SetPath("meta.source.system.name", "crm"); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.SetPath("user.name", "Jane"); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, expected := range map[string]interface{}{
+		"user.address.city":       "Bangkok",
+		"meta.source.system.name": "crm",
+		"user.name":               "Jane",
+	} {
+		if got, ok := j.GetPath(path); !ok || got != expected {
+			t.Errorf("%s: expected %v got %v", path, expected, got)
+		}
+	}
+
+	if err := j.SetPath("user.name.first", "John"); err == nil {
+		t.Error("expected error when a segment holds a scalar")
+	}
+	if err := JSONB(nil).SetPath("a", 1); err == nil {
+		t.Error("expected error for nil JSONB")
+	}
+}
+
+func TestJSONBRemovePath(t *testing.T) {
+	j := JSONB{
+		"user": map[string]interface{}{
+			"name":    "John",
+			"address": map[string]interface{}{"city": "Bangkok", "zip": "10110"},
+		},
+	}
+
+	if !j.RemovePath("user.address.zip") {
+		t.Error("expected leaf to be removed")
+	}
+	if _, ok := j.GetPath("user.address.zip"); ok {
+		t.Error("expected leaf to be gone")
+	}
+	if _, ok := j.GetPath("user.address.city"); !ok {
+		t.Error("expected sibling to be kept")
+	}
+
+	if !j.RemovePath("user.address") {
+		t.Error("expected intermediate node to be removed")
+	}
+	if _, ok := j.GetPath("user.address.city"); ok {
+		t.Error("expected intermediate node and its children to be gone")
+	}
+
+	if j.RemovePath("user.address") || j.RemovePath("user.name.first") || j.RemovePath("missing") {
+		t.Error("expected nothing to be removed for missing paths")
+	}
+	if !reflect.DeepEqual(j, JSONB{"user": map[string]interface{}{"name": "John"}}) {
+		t.Errorf("unexpected result %v", j)
+	}
+}
+
+func TestJSONBRedact(t *testing.T) {
+	body := JSONB{
+		"email":    "john@example.com",
+		"password": "secret",
+		"card":     map[string]interface{}{"number": "4242424242424242", "brand": "visa"},
+	}
+
+	redacted := body.Redact("password", "card.number", "card.missing")
+	expected := JSONB{
+		"email":    "john@example.com",
+		"password": "***",
+		"card":     map[string]interface{}{"number": "***", "brand": "visa"},
+	}
+	if !reflect.DeepEqual(redacted, expected) {
+		t.Errorf("expected %v got %v", expected, redacted)
+	}
+	if body["password"] != "secret" || body["card"].(map[string]interface{})["number"] != "4242424242424242" {
+		t.Error("expected original value to be left untouched")
+	}
+
+	nested := JSONB{
+		"user":     map[string]interface{}{"name": "John", "password": "secret"},
+		"accounts": []interface{}{map[string]interface{}{"password": "secret", "id": 1}},
+		"rows":     JSONBA{{"password": "secret"}},
+		"typed":    []JSONB{{"password": "secret"}},
+		"headers":  map[string]string{"password": "secret", "accept": "json"},
+		"card":     map[string]interface{}{"number": "4242", "meta": map[string]interface{}{"number": "kept"}},
+	}
+	expected = JSONB{
+		"user":     map[string]interface{}{"name": "John", "password": "***"},
+		"accounts": []interface{}{map[string]interface{}{"password": "***", "id": 1}},
+		"rows":     JSONBA{{"password": "***"}},
+		"typed":    []JSONB{{"password": "***"}},
+		"headers":  map[string]string{"password": "***", "accept": "json"},
+		"card":     map[string]interface{}{"number": "***", "meta": map[string]interface{}{"number": "kept"}},
+	}
+	if got := nested.Redact("password", "card.number"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected nested keys to be redacted, expected %v got %v", expected, got)
+	}
+	if nested["user"].(map[string]interface{})["password"] != "secret" || nested["typed"].([]JSONB)[0]["password"] != "secret" {
+		t.Error("expected original nested values to be left untouched")
+	}
+
+	literal := JSONB{"a*b": "secret", "ab": "kept", "x[1": "secret", "x1": "kept"}
+	expected = JSONB{"a*b": "***", "ab": "kept", "x[1": "***", "x1": "kept"}
+	if got := literal.Redact("a*b", "x[1"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected keys to be matched literally, expected %v got %v", expected, got)
+	}
+}
+
+func TestCSVToJSONBA(t *testing.T) {
+	data := []byte("\xEF\xBB\xBFname,city,note\n" +
+		"John,\"Bangkok, Thailand\",\"said \"\"hi\"\"\"\n" +
+		"Jane,Paris\n" +
+		"\"Multi\nLine\",Rome,\"\"\n")
+
+	rows, err := CSVToJSONBA(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := JSONBA{
+		{"name": "John", "city": "Bangkok, Thailand", "note": `said "hi"`},
+		{"name": "Jane", "city": "Paris", "note": ""},
+		{"name": "Multi\nLine", "city": "Rome", "note": ""},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected %v got %v", expected, rows)
+	}
+
+	if _, err := CSVToJSONBA([]byte("name,city\nJohn,Bangkok,extra\n")); err == nil {
+		t.Error("expected error for a row longer than the headers")
+	}
+	if _, err := CSVToJSONBA([]byte("name\n\"unterminated\n")); err == nil {
+		t.Error("expected error for malformed CSV")
+	}
+
+	for _, input := range []string{"", "name,city\n"} {
+		rows, err := CSVToJSONBA([]byte(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rows == nil || len(rows) != 0 {
+			t.Errorf("%q: expected empty JSONBA got %v", input, rows)
+		}
+	}
+}
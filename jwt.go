@@ -1,7 +1,14 @@
 package goease
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt"
@@ -74,7 +81,7 @@ type TokenClaims struct {
 This function takes two arguments: `tokenClaims` which is of type TokenClaims and contains the standard JWT claims like issuer (iss), subject (sub), audience (aud), and the expiration times for both access and refresh tokens. The second argument `additionalClaims` is a map of interface{} which allows adding extra information to the token.
 
 The function performs the following operations:
-1. It initializes the claims for the access token using both standard claims from `tokenClaims` and additional claims from `additionalClaims`.
+1. It initializes the claims for the access token using both standard claims from `tokenClaims` and additional claims from `additionalClaims`, with a random UUID "jti" for each token unless `additionalClaims` already provides one.
 2. It sets the "token_type" for the access token to "access".
 3. It calls `GenerateNewJwtTokenHelper` to create the JWT access token.
 4. It repeats similar steps for the refresh token, setting its "token_type" to "refresh".
@@ -106,6 +113,13 @@ func GenerateDynamicJWTWithClaimsHelper(tokenClaims TokenClaims, additionalClaim
 		"iat": time.Now().Unix(),
 		"exp": tokenClaims.AccessExp,
 	}
+	if _, ok := additionalClaims["jti"]; !ok {
+		jti, err := newTokenID()
+		if err != nil {
+			return "", "", err
+		}
+		accessTokenClaims["jti"] = jti
+	}
 
 	// Adding additional claims for access token
 	for key, value := range additionalClaims {
@@ -125,6 +139,13 @@ func GenerateDynamicJWTWithClaimsHelper(tokenClaims TokenClaims, additionalClaim
 		"iat": time.Now().Unix(),
 		"exp": tokenClaims.RefreshExp,
 	}
+	if _, ok := additionalClaims["jti"]; !ok {
+		jti, err := newTokenID()
+		if err != nil {
+			return "", "", err
+		}
+		refreshTokenClaims["jti"] = jti
+	}
 
 	for key, value := range additionalClaims {
 		refreshTokenClaims[key] = value
@@ -170,6 +191,74 @@ Note:
 - It's important that `configs.JWT_SECRET` is consistent with the secret key used for generating the tokens.
 */
 func DecodeTokenHelper(tokenString string, jwtSecret string) (jwt.MapClaims, error) {
+	token, err := parseHMACToken(tokenString, jwtSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	return token.Claims.(jwt.MapClaims), nil
+}
+
+/*
+	DecodeTokenWithHeader decodes and validates a JWT token string and returns both its header and its claims.
+
+This function performs the same validation as DecodeTokenHelper, but also returns the parsed header, which is useful for diagnostics (e.g. logging the "alg", "kid" and "typ" of the tokens a service receives).
+
+Parameters:
+- tokenString: string - The JWT token that needs to be decoded and validated.
+- jwtSecret: string - The secret key used for verifying the token.
+
+Returns:
+- map[string]interface{}: The header of the token, containing at least "alg".
+- jwt.MapClaims: The claims of the token.
+- error: An error if the token is malformed, its signature is invalid or its claims are not valid.
+*/
+func DecodeTokenWithHeader(tokenString, jwtSecret string) (header map[string]interface{}, claims jwt.MapClaims, err error) {
+	token, err := parseHMACToken(tokenString, jwtSecret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return token.Header, token.Claims.(jwt.MapClaims), nil
+}
+
+/*
+	DecodeTokenMultiSecret decodes and validates a JWT token string against several candidate secrets.
+
+This function supports secret rotation: the secrets are tried in order and the claims are returned as soon as one of them verifies the signature. Errors unrelated to the signature, such as an expired or malformed token, are returned immediately since no other secret could fix them.
+
+Parameters:
+- tokenString: string - The JWT token that needs to be decoded and validated.
+- secrets: ...string - The candidate secret keys, typically the current one followed by the previous one.
+
+Returns:
+- jwt.MapClaims: The claims of the token.
+- error: An error if no secret is given, no secret verifies the signature or the token is not valid.
+*/
+func DecodeTokenMultiSecret(tokenString string, secrets ...string) (jwt.MapClaims, error) {
+	if len(secrets) == 0 {
+		return nil, fmt.Errorf("no secret to verify the token with")
+	}
+
+	var err error
+	for _, secret := range secrets {
+		var token *jwt.Token
+		token, err = parseHMACToken(tokenString, secret)
+		if err == nil {
+			return token.Claims.(jwt.MapClaims), nil
+		}
+
+		var validationErr *jwt.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Errors != jwt.ValidationErrorSignatureInvalid {
+			return nil, err
+		}
+	}
+
+	return nil, err
+}
+
+// parseHMACToken parses a JWT token signed with an HMAC method and only returns it when it is valid.
+func parseHMACToken(tokenString, jwtSecret string) (*jwt.Token, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		// Don't forget to validate the alg is what you expect:
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -177,11 +266,395 @@ func DecodeTokenHelper(tokenString string, jwtSecret string) (jwt.MapClaims, err
 		}
 		return []byte(jwtSecret), nil
 	})
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := token.Claims.(jwt.MapClaims); !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	return token, nil
+}
+
+/*
+	TokenAlgorithm returns the signing algorithm declared in the header of a JWT token string.
+
+This function only base64url-decodes the header segment of the token and reads its "alg" value. It does NOT verify the signature or validate any claims, so the result must only be used to decide which verifier the token should be routed to.
+
+Parameters:
+- tokenString: string - The JWT token to inspect.
+
+Returns:
+- string: The "alg" value of the token header (e.g. "HS256", "RS256").
+- error: An error if the token is not made of three segments, the header cannot be decoded, or it has no "alg" value.
+*/
+func TokenAlgorithm(tokenString string) (string, error) {
+	parts := strings.Split(tokenString, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("token contains an invalid number of segments")
+	}
+
+	headerBytes, err := jwt.DecodeSegment(parts[0])
+	if err != nil {
+		return "", fmt.Errorf("failed to decode token header: %w", err)
+	}
+
+	var header map[string]interface{}
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return "", fmt.Errorf("failed to decode token header: %w", err)
+	}
+
+	alg, ok := header["alg"].(string)
+	if !ok || alg == "" {
+		return "", fmt.Errorf("token header has no alg")
+	}
+
+	return alg, nil
+}
+
+/*
+	IsTokenExpired reports whether the "exp" claim of a JWT token string is in the past.
+
+This function parses the token claims WITHOUT verifying the signature, so it must only be used for quick client-side checks (e.g. deciding whether to refresh a token before sending it). Use `DecodeTokenHelper` whenever the token needs to be trusted.
+
+Parameters:
+- tokenString: string - The JWT token to inspect.
+
+Returns:
+- bool: true if the token has expired, false otherwise.
+- error: An error if the token cannot be parsed or has no "exp" claim.
+*/
+func IsTokenExpired(tokenString string) (bool, error) {
+	claims := jwt.MapClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(tokenString, claims); err != nil {
+		return false, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	if _, ok := claims["exp"]; !ok {
+		return false, fmt.Errorf("token has no exp claim")
+	}
+
+	return !claims.VerifyExpiresAt(time.Now().Unix(), true), nil
+}
+
+/*
+	RedactClaims returns a copy of the claims containing only the whitelisted keys.
+
+This function is meant for logging tokens without leaking sensitive custom claims. The input claims are left untouched, and whitelisted keys absent from the claims are simply skipped.
+
+Parameters:
+- claims: jwt.MapClaims - The claims to redact.
+- keep: ...string - The claim keys to keep (e.g. "sub", "iss", "exp").
+
+Returns:
+- jwt.MapClaims: A new map holding only the whitelisted claims.
+*/
+func RedactClaims(claims jwt.MapClaims, keep ...string) jwt.MapClaims {
+	redacted := jwt.MapClaims{}
+	for _, key := range keep {
+		if value, ok := claims[key]; ok {
+			redacted[key] = value
+		}
+	}
+
+	return redacted
+}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return claims, nil
-	} else {
+// TokenValidator reports whether a token has been revoked before its natural expiry (e.g. on logout).
+type TokenValidator interface {
+	// IsRevoked reports whether the token with the given jti claim has been revoked.
+	IsRevoked(jti string) bool
+}
+
+/*
+	DecodeTokenWithRevocation decodes and validates a JWT token string like DecodeTokenHelper, and additionally rejects revoked tokens.
+
+After the token has been decoded and validated, its "jti" claim is looked up in the given TokenValidator. Tokens without a "jti" claim are rejected, as their revocation cannot be checked.
+
+Parameters:
+- tokenString: string - The JWT token that needs to be decoded and validated.
+- jwtSecret: string - The secret key used for validating the token signature.
+- v: TokenValidator - The validator telling whether the token has been revoked.
 
+Returns:
+- jwt.MapClaims: A map of claims extracted from the token if it is valid and not revoked.
+- error: An error if the token is invalid, has no "jti" claim, or has been revoked.
+*/
+func DecodeTokenWithRevocation(tokenString, jwtSecret string, v TokenValidator) (jwt.MapClaims, error) {
+	claims, err := DecodeTokenHelper(tokenString, jwtSecret)
+	if err != nil {
 		return nil, err
 	}
+
+	jti, ok := claims["jti"].(string)
+	if !ok || jti == "" {
+		return nil, fmt.Errorf("token has no jti claim")
+	}
+	if v.IsRevoked(jti) {
+		return nil, fmt.Errorf("token has been revoked")
+	}
+
+	return claims, nil
+}
+
+/*
+	MapRevocationStore is an in-memory TokenValidator, safe for concurrent use.
+
+Every revoked jti is kept along with the expiry of its token: once the token has expired it would be rejected anyway, so the entry is no longer needed and is dropped by Cleanup (or lazily by IsRevoked). Being in-memory, the store is neither persisted nor shared between instances of an application.
+
+Example Usage:
+
+	store := NewMapRevocationStore()
+	store.Revoke(claims["jti"].(string), time.Unix(int64(claims["exp"].(float64)), 0))
+	claims, err := DecodeTokenWithRevocation(tokenString, jwtSecret, store)
+*/
+type MapRevocationStore struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time
+}
+
+// NewMapRevocationStore creates an empty MapRevocationStore.
+func NewMapRevocationStore() *MapRevocationStore {
+	return &MapRevocationStore{revoked: make(map[string]time.Time)}
+}
+
+// Revoke marks the token with the given jti as revoked until its expiry.
+func (s *MapRevocationStore) Revoke(jti string, expiresAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revoked[jti] = expiresAt
+}
+
+// IsRevoked reports whether the token with the given jti has been revoked and has not expired yet.
+func (s *MapRevocationStore) IsRevoked(jti string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiresAt, ok := s.revoked[jti]
+	if !ok {
+		return false
+	}
+	if time.Now().After(expiresAt) {
+		delete(s.revoked, jti)
+		return false
+	}
+	return true
+}
+
+// Cleanup drops the entries of expired tokens and returns how many were dropped.
+// It is meant to be called periodically, e.g. from a time.Ticker.
+func (s *MapRevocationStore) Cleanup() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	removed := 0
+	for jti, expiresAt := range s.revoked {
+		if now.After(expiresAt) {
+			delete(s.revoked, jti)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Len returns the number of revoked tokens held by the store.
+func (s *MapRevocationStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.revoked)
+}
+
+/*
+	GenerateSimpleToken creates an HS256 signed JWT token with the standard claims populated automatically.
+
+This function reduces the boilerplate of building claims by hand: it sets "sub" to the given subject, "iat" to now, "exp" to now plus the given time-to-live and "jti" to a random UUID, then merges the extra claims. Since the standard claims are only defaults, an extra claim with the same name overrides them.
+
+Parameters:
+- subject: string - The subject of the token, typically a user ID.
+- ttl: time.Duration - How long the token remains valid.
+- extra: map[string]interface{} - Additional claims to include in the token. It may be nil.
+- secret: string - The secret key used for signing the token.
+
+Returns:
+- string: The generated JWT token.
+- error: An error if the random identifier or the token cannot be generated.
+*/
+func GenerateSimpleToken(subject string, ttl time.Duration, extra map[string]interface{}, secret string) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub": subject,
+		"iat": now.Unix(),
+		"exp": now.Add(ttl).Unix(),
+		"jti": jti,
+	}
+	for key, value := range extra {
+		claims[key] = value
+	}
+
+	return GenerateNewJwtTokenHelper(claims, []byte(secret))
+}
+
+// newTokenID returns a random (version 4) UUID, suitable for the "jti" claim.
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token id: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+/*
+	ExtractBearerToken returns the token carried by an Authorization header using the Bearer scheme.
+
+The "Bearer" prefix is matched case-insensitively and surrounding whitespace is ignored, so "Bearer abc", "bearer abc" and "  BEARER   abc " all return "abc".
+
+Parameters:
+- authHeader: string - The value of the Authorization header.
+
+Returns:
+- string: The token without the scheme.
+- error: An error if the header is empty, does not use the Bearer scheme or does not carry a token.
+*/
+func ExtractBearerToken(authHeader string) (string, error) {
+	authHeader = strings.TrimSpace(authHeader)
+	if authHeader == "" {
+		return "", fmt.Errorf("authorization header is empty")
+	}
+
+	scheme, token, found := strings.Cut(authHeader, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("authorization header does not use the Bearer scheme")
+	}
+	token = strings.TrimSpace(token)
+	if !found || token == "" || strings.ContainsAny(token, " \t") {
+		return "", fmt.Errorf("authorization header does not carry a valid bearer token")
+	}
+
+	return token, nil
+}
+
+// claimsContextKey is the type of ClaimsContextKey, unexported to avoid collisions with keys defined in other packages.
+type claimsContextKey struct{}
+
+// ClaimsContextKey is the request context key under which JWTMiddleware stores the jwt.MapClaims of an authenticated request.
+// Prefer ClaimsFromContext to read them.
+var ClaimsContextKey = claimsContextKey{}
+
+/*
+	JWTMiddleware authenticates requests with an HS256 JWT token sent as a Bearer token.
+
+The token is extracted with ExtractBearerToken and validated with DecodeTokenHelper. On success, its claims are stored in the request context under ClaimsContextKey and the request is passed to next. Otherwise, next is not called and a 401 Unauthorized response is written.
+
+Parameters:
+- jwtSecret: string - The secret key used for verifying the tokens.
+- next: http.Handler - The handler serving authenticated requests.
+
+Returns:
+- http.Handler: The authenticating handler.
+
+Example:
+
+	mux.Handle("/me", JWTMiddleware(secret, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := ClaimsFromContext(r.Context())
+		fmt.Fprintln(w, claims["sub"])
+	})))
+*/
+func JWTMiddleware(jwtSecret string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenString, err := ExtractBearerToken(r.Header.Get("Authorization"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		claims, err := DecodeTokenHelper(tokenString, jwtSecret)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), ClaimsContextKey, claims)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// NewJWTMiddleware returns JWTMiddleware as a func(http.Handler) http.Handler, the form expected by most routers
+// (e.g. chi's Use).
+func NewJWTMiddleware(jwtSecret string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return JWTMiddleware(jwtSecret, next)
+	}
+}
+
+// ClaimsFromContext returns the claims stored by JWTMiddleware in a request context.
+// The boolean is false when the context does not hold any claims.
+func ClaimsFromContext(ctx context.Context) (jwt.MapClaims, bool) {
+	claims, ok := ctx.Value(ClaimsContextKey).(jwt.MapClaims)
+	return claims, ok
+}
+
+/*
+	HasScope reports whether the "scope" claim of a token grants the given scope.
+
+The claim may either be an OAuth style space-delimited string (e.g. "read:users write:users") or an array of strings.
+
+Parameters:
+- claims: jwt.MapClaims - The claims of a decoded token.
+- scope: string - The required scope.
+
+Returns:
+- bool: true if the scope is granted, false otherwise or if the claim is absent.
+*/
+func HasScope(claims jwt.MapClaims, scope string) bool {
+	return Contains(claimValues(claims, "scope"), scope)
+}
+
+/*
+	HasAnyRole reports whether the "roles" claim of a token contains at least one of the given roles.
+
+The claim may either be an array of strings or a space-delimited string.
+
+Parameters:
+- claims: jwt.MapClaims - The claims of a decoded token.
+- roles: ...string - The accepted roles.
+
+Returns:
+- bool: true if one of the roles is found, false otherwise or if the claim is absent.
+*/
+func HasAnyRole(claims jwt.MapClaims, roles ...string) bool {
+	granted := claimValues(claims, "roles")
+	for _, role := range roles {
+		if Contains(granted, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// claimValues returns the values of a claim holding either a space-delimited string or an array of strings.
+func claimValues(claims jwt.MapClaims, name string) []string {
+	switch v := claims[name].(type) {
+	case string:
+		return strings.Fields(v)
+	case []string:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
 }
@@ -0,0 +1,65 @@
+package goease
+
+import (
+	"cmp"
+	"slices"
+)
+
+// DiffCounts computes the per-key difference between two maps of counts (newCounts - oldCounts).
+// Keys present in only one of the maps are included, a missing key counting as 0, so removed keys give negative deltas.
+// Example usage:
+// delta := DiffCounts(map[string]int{"a": 1, "b": 5}, map[string]int{"b": 3, "c": 2})
+// fmt.Println("Delta:", delta) // map[a:-1 b:-2 c:2]
+func DiffCounts(oldCounts, newCounts map[string]int) map[string]int {
+	delta := make(map[string]int, len(newCounts))
+	for key, count := range newCounts {
+		delta[key] = count
+	}
+	for key, count := range oldCounts {
+		delta[key] -= count
+	}
+	return delta
+}
+
+// MapKeys returns the keys of a map, in no particular order. Use SortedMapKeys for a deterministic order.
+// Example usage:
+// keys := MapKeys(JSONB{"name": "John", "age": 30}) // [name age] or [age name]
+func MapKeys[K comparable, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// MapValues returns the values of a map, in no particular order. Use SortedMapValues for the values ordered by key.
+// Example usage:
+// values := MapValues(map[string]int{"a": 1, "b": 2}) // [1 2] or [2 1]
+func MapValues[K comparable, V any](m map[K]V) []V {
+	values := make([]V, 0, len(m))
+	for _, value := range m {
+		values = append(values, value)
+	}
+	return values
+}
+
+// SortedMapKeys returns the keys of a map in ascending order.
+// Example usage:
+// keys := SortedMapKeys(map[int]string{3: "c", 1: "a"}) // [1 3]
+func SortedMapKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := MapKeys(m)
+	slices.Sort(keys)
+	return keys
+}
+
+// SortedMapValues returns the values of a map ordered by their key.
+// Example usage:
+// values := SortedMapValues(map[int]string{3: "c", 1: "a"}) // [a c]
+func SortedMapValues[K cmp.Ordered, V any](m map[K]V) []V {
+	keys := SortedMapKeys(m)
+	values := make([]V, len(keys))
+	for i, key := range keys {
+		values[i] = m[key]
+	}
+	return values
+}
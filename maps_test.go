@@ -0,0 +1,51 @@
+package goease
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestDiffCounts(t *testing.T) {
+	oldCounts := map[string]int{"removed": 4, "changed": 5, "same": 2}
+	newCounts := map[string]int{"added": 3, "changed": 8, "same": 2}
+
+	expected := map[string]int{"added": 3, "removed": -4, "changed": 3, "same": 0}
+	if got := DiffCounts(oldCounts, newCounts); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v got %v", expected, got)
+	}
+
+	if got := DiffCounts(nil, nil); len(got) != 0 {
+		t.Errorf("expected empty delta got %v", got)
+	}
+}
+
+func TestMapKeysAndValues(t *testing.T) {
+	m := map[string]int{"b": 2, "a": 1, "c": 3}
+
+	keys := MapKeys(m)
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Errorf("unexpected keys %v", keys)
+	}
+	values := MapValues(m)
+	sort.Ints(values)
+	if !reflect.DeepEqual(values, []int{1, 2, 3}) {
+		t.Errorf("unexpected values %v", values)
+	}
+
+	if got := MapKeys(map[string]int{}); got == nil || len(got) != 0 {
+		t.Errorf("expected an empty slice got %v", got)
+	}
+}
+
+func TestSortedMapKeysAndValues(t *testing.T) {
+	m := map[int]string{30: "c", 10: "a", 20: "b"}
+
+	if got := SortedMapKeys(m); !reflect.DeepEqual(got, []int{10, 20, 30}) {
+		t.Errorf("unexpected keys %v", got)
+	}
+	if got := SortedMapValues(m); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("unexpected values %v", got)
+	}
+}
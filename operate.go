@@ -1,9 +1,16 @@
 package goease
 
 import (
+	"bytes"
 	"encoding/base64"
 	"fmt"
+	"html"
+	"net/http"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // SplitString splits a string into an array of substrings based on a delimiter.
@@ -11,10 +18,52 @@ func SplitString(input, delimiter string) []string {
 	return strings.Split(input, delimiter)
 }
 
+// SplitStringN splits a string into at most n substrings based on a delimiter, the last substring holding the unsplit remainder.
+// n < 0 means no limit, as with strings.SplitN.
+func SplitStringN(input, delimiter string, n int) []string {
+	return strings.SplitN(input, delimiter, n)
+}
+
+// SplitAndTrim splits a string based on a delimiter, trims the whitespace around every substring and drops the empty ones.
+func SplitAndTrim(input, delimiter string) []string {
+	result := []string{}
+	for _, part := range strings.Split(input, delimiter) {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
 func ToLowerCase(text string) string {
 	return strings.ToLower(text)
 }
 
+// Truncate cuts a string to at most max runes, appending ellipsis if it was cut.
+// Runes are counted rather than bytes, so multibyte characters are never split.
+func Truncate(s string, max int, ellipsis string) string {
+	if max < 0 {
+		max = 0
+	}
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max]) + ellipsis
+}
+
+// TruncateWords keeps the first n whitespace-separated words of a string.
+// The string is returned untouched if it has n words or fewer, otherwise the kept words are joined with single spaces.
+func TruncateWords(s string, n int) string {
+	words := strings.Fields(s)
+	if len(words) <= n {
+		return s
+	}
+	if n < 0 {
+		n = 0
+	}
+	return strings.Join(words[:n], " ")
+}
+
 // DecodeBase64 decodes a base64 string into binary data.
 //
 // This function takes a base64 encoded string as input and decodes it into its binary representation. It returns the decoded binary data and any error encountered during the decoding process.
@@ -84,3 +133,290 @@ func ExtractImageTypeFromBase64(dataURI string) (string, error) {
 	imageType := dataURI[len("data:image/"):endIndex]
 	return imageType, nil
 }
+
+// JaccardSimilarity computes the Jaccard similarity of two string sets.
+//
+// Both slices are treated as sets, so duplicated values are only counted once. The similarity is the size of the intersection divided by the size of the union, ranging from 0 (no common value) to 1 (same values). Two empty sets are considered identical and have a similarity of 1.
+//
+// Parameters:
+//   - a: []string - The first set of values.
+//   - b: []string - The second set of values.
+//
+// Returns:
+//   - float64: The Jaccard similarity between 0 and 1.
+//
+// Example:
+//
+//	similarity := JaccardSimilarity([]string{"go", "sql"}, []string{"go", "api"})
+//
+// This will return 0.333..., as one value is shared out of three distinct values.
+func JaccardSimilarity(a, b []string) float64 {
+	setA := make(map[string]struct{}, len(a))
+	for _, item := range a {
+		setA[item] = struct{}{}
+	}
+
+	union := make(map[string]struct{}, len(a)+len(b))
+	for item := range setA {
+		union[item] = struct{}{}
+	}
+
+	intersection := 0
+	for _, item := range Unique(b) {
+		if _, ok := setA[item]; ok {
+			intersection++
+		}
+		union[item] = struct{}{}
+	}
+
+	if len(union) == 0 {
+		return 1
+	}
+	return float64(intersection) / float64(len(union))
+}
+
+// NormalizeEmail normalizes an email address so that equivalent addresses can be compared.
+//
+// Surrounding whitespace is trimmed and the domain is lowercased. The local part is kept as is, since it is case-sensitive in general. When 'gmailRules' is true and the domain is gmail.com or googlemail.com, the local part is also lowercased, its dots are removed and any "+tag" suffix is stripped, as Gmail ignores them when delivering.
+//
+// Parameters:
+//   - email: string - The email address to normalize.
+//   - gmailRules: bool - Whether to apply the Gmail specific dot and "+tag" handling.
+//
+// Returns:
+//   - string: The normalized email address. A value without "@" is only trimmed.
+//
+// Example:
+//
+//	email := NormalizeEmail(" John.Doe+news@GMail.com ", true)
+//
+// This will return "johndoe@gmail.com".
+func NormalizeEmail(email string, gmailRules bool) string {
+	email = strings.TrimSpace(email)
+
+	at := strings.LastIndex(email, "@")
+	if at == -1 {
+		return email
+	}
+	local, domain := email[:at], strings.ToLower(email[at+1:])
+
+	if gmailRules && (domain == "gmail.com" || domain == "googlemail.com") {
+		local = strings.ToLower(local)
+		if plus := strings.Index(local, "+"); plus != -1 {
+			local = local[:plus]
+		}
+		local = strings.ReplaceAll(local, ".", "")
+	}
+
+	return local + "@" + domain
+}
+
+// EmailsEqual reports whether two email addresses are equivalent once normalized with NormalizeEmail, including the Gmail specific rules.
+func EmailsEqual(a, b string) bool {
+	return NormalizeEmail(a, true) == NormalizeEmail(b, true)
+}
+
+// utf8BOM is the byte-order mark some editors prepend to UTF-8 files.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// StripBOM removes a leading UTF-8 byte-order mark from the input, if present.
+//
+// Parameters:
+//   - data: []byte - The input bytes.
+//
+// Returns:
+//   - []byte: The input without its leading BOM. Input without a BOM is returned untouched.
+func StripBOM(data []byte) []byte {
+	return bytes.TrimPrefix(data, utf8BOM)
+}
+
+var (
+	// htmlScriptStyleRX matches script and style elements, whose content is not text.
+	htmlScriptStyleRX = regexp.MustCompile(`(?is)<(script|style)\b[^>]*>.*?</(script|style)\s*>`)
+
+	// htmlTagRX matches any HTML tag or comment.
+	htmlTagRX = regexp.MustCompile(`(?s)<!--.*?-->|</?[a-zA-Z][^>]*>`)
+)
+
+// StripHTMLTags removes HTML tags from a string, keeping their inner text.
+//
+// The content of script and style elements and HTML comments are removed as well, and HTML entities such as "&amp;" are decoded. This is a tag stripper for displaying user-submitted text, not a full HTML sanitizer: since entities are decoded, the result must still be escaped when rendered back into HTML.
+//
+// Parameters:
+//   - input: string - The HTML text.
+//
+// Returns:
+//   - string: The text without tags and with entities decoded.
+//
+// Example:
+//
+//	text := StripHTMLTags("<p>Fish &amp; <b>Chips</b><br/></p>")
+//
+// This will return "Fish & Chips".
+func StripHTMLTags(input string) string {
+	stripped := htmlScriptStyleRX.ReplaceAllString(input, "")
+	stripped = htmlTagRX.ReplaceAllString(stripped, "")
+	return html.UnescapeString(stripped)
+}
+
+// slugDiacritics maps lowercase Latin letters with diacritics to their ASCII equivalent.
+var slugDiacritics = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'ç': "c", 'ć': "c", 'ĉ': "c", 'ċ': "c", 'č': "c",
+	'ď': "d", 'đ': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ĕ': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ĝ': "g", 'ğ': "g", 'ġ': "g", 'ģ': "g",
+	'ĥ': "h", 'ħ': "h",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ĩ': "i", 'ī': "i", 'ĭ': "i", 'į': "i", 'ı': "i",
+	'ĵ': "j",
+	'ķ': "k",
+	'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ŀ': "l", 'ł': "l",
+	'ñ': "n", 'ń': "n", 'ņ': "n", 'ň': "n", 'ŉ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ŏ': "o", 'ő': "o",
+	'ŕ': "r", 'ŗ': "r", 'ř': "r",
+	'ś': "s", 'ŝ': "s", 'ş': "s", 'š': "s",
+	'ţ': "t", 'ť': "t", 'ŧ': "t",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ũ': "u", 'ū': "u", 'ŭ': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ŵ': "w",
+	'ý': "y", 'ÿ': "y", 'ŷ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'þ': "th", 'ð': "d",
+}
+
+// Slugify converts a string into a URL slug.
+//
+// The string is lowercased, diacritics are stripped from Latin letters (e.g. "é" becomes "e"), and every run of whitespace, punctuation or symbols is replaced by a single hyphen. Leading and trailing hyphens are trimmed. Letters and digits of other scripts are kept as is.
+//
+// Parameters:
+//   - s: string - The text to convert, typically a title.
+//
+// Returns:
+//   - string: The slug, e.g. "Héllo, World!" gives "hello-world".
+func Slugify(s string) string {
+	var sb strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(s) {
+		var part string
+		if ascii, ok := slugDiacritics[r]; ok {
+			part = ascii
+		} else if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			part = string(r)
+		} else if unicode.Is(unicode.Mn, r) {
+			continue
+		} else {
+			pendingHyphen = true
+			continue
+		}
+
+		if pendingHyphen && sb.Len() > 0 {
+			sb.WriteByte('-')
+		}
+		pendingHyphen = false
+		sb.WriteString(part)
+	}
+	return sb.String()
+}
+
+// ReverseString reverses a string rune by rune, so multibyte characters such as
+// "é" or emojis are never split. Combining marks stay attached to the character
+// they follow, so a decomposed "e\u0301" is kept as is.
+func ReverseString(s string) string {
+	runes := []rune(s)
+	result := make([]rune, 0, len(runes))
+	for end := len(runes); end > 0; {
+		start := end - 1
+		for start > 0 && unicode.Is(unicode.Mn, runes[start]) {
+			start--
+		}
+		result = append(result, runes[start:end]...)
+		end = start
+	}
+	return string(result)
+}
+
+// maxFilenameLength is the maximum length in bytes of a sanitized filename, the limit of most file systems.
+const maxFilenameLength = 255
+
+// SanitizeFilename makes a user-provided filename safe for storage.
+//
+// Path separators and characters reserved on Windows (:*?"<>|) are replaced by underscores, control characters are removed, runs of whitespace are collapsed into a single space, and leading dots and spaces are trimmed so the name can neither be hidden nor point to a parent directory. Names longer than 255 bytes are truncated without splitting multibyte characters, keeping their extension.
+//
+// Parameters:
+//   - name: string - The filename to sanitize.
+//
+// Returns:
+//   - string: The sanitized filename, or "unnamed" if nothing is left.
+//
+// Example:
+//
+//	name := SanitizeFilename("../../etc/pass  wd.txt")
+//
+// This will return "_.._etc_pass wd.txt".
+func SanitizeFilename(name string) string {
+	var sb strings.Builder
+	lastSpace := false
+	for _, r := range name {
+		switch {
+		case strings.ContainsRune(`/\:*?"<>|`, r):
+			sb.WriteRune('_')
+		case unicode.IsSpace(r):
+			if !lastSpace {
+				sb.WriteRune(' ')
+			}
+			lastSpace = true
+			continue
+		case unicode.IsControl(r):
+			continue
+		default:
+			sb.WriteRune(r)
+		}
+		lastSpace = false
+	}
+
+	sanitized := strings.TrimRight(strings.TrimLeft(sb.String(), ". "), " ")
+	if sanitized == "" {
+		return "unnamed"
+	}
+	if len(sanitized) <= maxFilenameLength {
+		return sanitized
+	}
+
+	ext := filepath.Ext(sanitized)
+	if len(ext) > maxFilenameLength/2 {
+		ext = ""
+	}
+	base := sanitized[:len(sanitized)-len(ext)]
+	cut := maxFilenameLength - len(ext)
+	for cut > 0 && !utf8.RuneStart(base[cut]) {
+		cut--
+	}
+	return strings.TrimRight(base[:cut], " ") + ext
+}
+
+// DetectContentType detects the MIME type of data from its first bytes.
+//
+// This function wraps http.DetectContentType, first checking the signatures of modern image formats it does not know about (AVIF, HEIC and JPEG XL) as well as WebP. It always returns a valid MIME type, falling back to "application/octet-stream".
+//
+// Parameters:
+//   - data: []byte - The content, of which at most the first 512 bytes are considered.
+//
+// Returns:
+//   - string: The detected MIME type, e.g. "image/webp".
+func DetectContentType(data []byte) string {
+	switch {
+	case len(data) >= 12 && bytes.Equal(data[0:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WEBP")):
+		return "image/webp"
+	case len(data) >= 12 && bytes.Equal(data[4:8], []byte("ftyp")):
+		switch string(data[8:12]) {
+		case "avif", "avis":
+			return "image/avif"
+		case "heic", "heix", "heim", "heis":
+			return "image/heic"
+		}
+	case bytes.HasPrefix(data, []byte{0xFF, 0x0A}),
+		bytes.HasPrefix(data, []byte{0x00, 0x00, 0x00, 0x0C, 'J', 'X', 'L', ' ', 0x0D, 0x0A, 0x87, 0x0A}):
+		return "image/jxl"
+	}
+
+	return http.DetectContentType(data)
+}
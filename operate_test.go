@@ -0,0 +1,237 @@
+package goease
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestJaccardSimilarity(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     []string
+		expected float64
+	}{
+		{"identical", []string{"go", "sql", "go"}, []string{"sql", "go"}, 1},
+		{"disjoint", []string{"go", "sql"}, []string{"api", "db"}, 0},
+		{"partial", []string{"go", "sql"}, []string{"go", "api"}, 1.0 / 3},
+		{"empty", nil, []string{}, 1},
+		{"one empty", []string{"go"}, nil, 0},
+	}
+	for _, tt := range tests {
+		if got := JaccardSimilarity(tt.a, tt.b); math.Abs(got-tt.expected) > 1e-9 {
+			t.Errorf("%s: expected %v got %v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		input      string
+		gmailRules bool
+		expected   string
+	}{
+		{"  John@Example.COM ", false, "John@example.com"},
+		{"John.Doe+news@GMail.com", false, "John.Doe+news@gmail.com"},
+		{"John.Doe+news@GMail.com", true, "johndoe@gmail.com"},
+		{"j.o.h.n@googlemail.com", true, "john@googlemail.com"},
+		{"john.doe+news@example.com", true, "john.doe+news@example.com"},
+		{" not-an-email ", true, "not-an-email"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeEmail(tt.input, tt.gmailRules); got != tt.expected {
+			t.Errorf("NormalizeEmail(%q, %v): expected %q got %q", tt.input, tt.gmailRules, tt.expected, got)
+		}
+	}
+}
+
+func TestEmailsEqual(t *testing.T) {
+	if !EmailsEqual(" john@EXAMPLE.com", "john@example.com ") {
+		t.Error("expected emails with different domain case to be equal")
+	}
+	if !EmailsEqual("john.doe+news@gmail.com", "JohnDoe@gmail.com") {
+		t.Error("expected gmail addresses to be equal")
+	}
+	if EmailsEqual("john.doe@example.com", "johndoe@example.com") {
+		t.Error("expected non gmail addresses with dots to differ")
+	}
+}
+
+func TestStripBOM(t *testing.T) {
+	withBOM := append([]byte{0xEF, 0xBB, 0xBF}, []byte(`{"name":"John"}`)...)
+	if got := StripBOM(withBOM); string(got) != `{"name":"John"}` {
+		t.Errorf("expected BOM to be stripped got %q", got)
+	}
+
+	withoutBOM := []byte(`{"name":"John"}`)
+	if got := StripBOM(withoutBOM); string(got) != `{"name":"John"}` {
+		t.Errorf("expected input to be untouched got %q", got)
+	}
+}
+
+func TestStripHTMLTags(t *testing.T) {
+	tests := map[string]string{
+		"<div><p>Hello <b>big <i>world</i></b></p></div>":     "Hello big world",
+		"line<br/>break<hr />end<img src=\"x.png\">":          "linebreakend",
+		"Fish &amp; Chips &lt;3 &quot;yum&quot; &#39;ok&#39;": "Fish & Chips <3 \"yum\" 'ok'",
+		"<script>alert('x')</script>safe<style>p{}</style>":   "safe",
+		"a <!-- hidden --> b":                                 "a  b",
+		"1 < 2 and 3 > 2":                                     "1 < 2 and 3 > 2",
+	}
+	for input, expected := range tests {
+		if got := StripHTMLTags(input); got != expected {
+			t.Errorf("%q: expected %q got %q", input, expected, got)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		input    string
+		max      int
+		expected string
+	}{
+		{"hello world", 5, "hello..."},
+		{"hello", 5, "hello"},
+		{"hello", 10, "hello"},
+		{"สวัสดีครับ", 3, "สวั..."},
+		{"👋🌍🚀✨", 2, "👋🌍..."},
+		{"hello", 0, "..."},
+	}
+	for _, tt := range tests {
+		got := Truncate(tt.input, tt.max, "...")
+		if got != tt.expected {
+			t.Errorf("Truncate(%q, %d): expected %q got %q", tt.input, tt.max, tt.expected, got)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("Truncate(%q, %d): produced invalid UTF-8 %q", tt.input, tt.max, got)
+		}
+	}
+}
+
+func TestTruncateWords(t *testing.T) {
+	tests := []struct {
+		input    string
+		n        int
+		expected string
+	}{
+		{"the quick  brown\tfox", 2, "the quick"},
+		{"the quick brown fox", 4, "the quick brown fox"},
+		{"hi 👋 there 🌍", 2, "hi 👋"},
+		{"one two", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := TruncateWords(tt.input, tt.n); got != tt.expected {
+			t.Errorf("TruncateWords(%q, %d): expected %q got %q", tt.input, tt.n, tt.expected, got)
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Héllo, World!":                  "hello-world",
+		"  Multiple   spaces\there  ":    "multiple-spaces-here",
+		"Crème Brûlée & Café Ñandú":      "creme-brulee-cafe-nandu",
+		"Straße 42 -- Øresund":           "strasse-42-oresund",
+		"$$$ 100% off!!! (today only) $": "100-off-today-only",
+		"already-a-slug":                 "already-a-slug",
+		"e\u0301te\u0301":                "ete",
+		"":                               "",
+	}
+	for input, expected := range tests {
+		if got := Slugify(input); got != expected {
+			t.Errorf("%q: expected %q got %q", input, expected, got)
+		}
+	}
+}
+
+func TestReverseString(t *testing.T) {
+	tests := map[string]string{
+		"hello":          "olleh",
+		"héllo":          "olléh",
+		"สวัสดี":         "ดีสวัส",
+		"go 👋🌍":          "🌍👋 og",
+		"cafe\u0301 bar": "rab e\u0301fac",
+		"":               "",
+	}
+	for input, expected := range tests {
+		got := ReverseString(input)
+		if got != expected {
+			t.Errorf("%q: expected %q got %q", input, expected, got)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("%q: produced invalid UTF-8 %q", input, got)
+		}
+	}
+}
+
+func TestSplitStringN(t *testing.T) {
+	if got := SplitStringN("key=value=with=equals", "=", 2); !reflect.DeepEqual(got, []string{"key", "value=with=equals"}) {
+		t.Errorf("unexpected result %q", got)
+	}
+	if got := SplitStringN("a,b,c", ",", -1); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("unexpected result %q", got)
+	}
+	if got := SplitStringN("a,b,", ",", 5); !reflect.DeepEqual(got, []string{"a", "b", ""}) {
+		t.Errorf("unexpected result %q", got)
+	}
+}
+
+func TestSplitAndTrim(t *testing.T) {
+	if got := SplitAndTrim(" go , sql,, api ,", ","); !reflect.DeepEqual(got, []string{"go", "sql", "api"}) {
+		t.Errorf("unexpected result %q", got)
+	}
+	if got := SplitAndTrim(" , ,", ","); len(got) != 0 {
+		t.Errorf("expected no element got %q", got)
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := map[string]string{
+		"../../etc/pass  wd.txt":  "_.._etc_pass wd.txt",
+		`C:\Users\john\cv.pdf`:    "C__Users_john_cv.pdf",
+		"  .hidden\t\nfile .png ": "hidden file .png",
+		"report\x00\x1f?.pdf":     "report_.pdf",
+		"รายงาน ประจำปี.pdf":      "รายงาน ประจำปี.pdf",
+		"...": "unnamed",
+	}
+	for input, expected := range tests {
+		if got := SanitizeFilename(input); got != expected {
+			t.Errorf("%q: expected %q got %q", input, expected, got)
+		}
+	}
+
+	long := SanitizeFilename(strings.Repeat("a", 300) + ".tar.gz")
+	if len(long) != 255 || !strings.HasSuffix(long, "a.gz") {
+		t.Errorf("expected a 255 bytes name keeping its extension got %d bytes %q", len(long), long)
+	}
+
+	longUnicode := SanitizeFilename(strings.Repeat("ก", 200) + ".txt")
+	if len(longUnicode) > 255 || !utf8.ValidString(longUnicode) || !strings.HasSuffix(longUnicode, "ก.txt") {
+		t.Errorf("expected a valid name of at most 255 bytes keeping its extension got %d bytes %q", len(longUnicode), longUnicode)
+	}
+}
+
+func TestDetectContentType(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "image/png"},
+		{"jpeg", []byte("\xFF\xD8\xFF\xE0\x00\x10JFIF\x00"), "image/jpeg"},
+		{"pdf", []byte("%PDF-1.7\n%\xE2\xE3\xCF\xD3"), "application/pdf"},
+		{"webp", []byte("RIFF\x24\x00\x00\x00WEBPVP8 "), "image/webp"},
+		{"avif", []byte("\x00\x00\x00\x20ftypavif\x00\x00\x00\x00"), "image/avif"},
+		{"heic", []byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00"), "image/heic"},
+		{"jxl", []byte("\xFF\x0A\xFA\x7F"), "image/jxl"},
+		{"unknown", []byte{0x00, 0x01, 0x02, 0x03}, "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		if got := DetectContentType(tt.data); got != tt.expected {
+			t.Errorf("%s: expected %q got %q", tt.name, tt.expected, got)
+		}
+	}
+}
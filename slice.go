@@ -0,0 +1,216 @@
+package goease
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Map applies a function to every element of a slice and returns the results.
+// A nil slice returns nil.
+// Example usage:
+// lengths := Map([]string{"a", "bb"}, func(s string) int { return len(s) })
+// fmt.Println("Lengths:", lengths) // [1 2]
+func Map[T, U any](s []T, f func(T) U) []U {
+	if s == nil {
+		return nil
+	}
+
+	result := make([]U, len(s))
+	for i, item := range s {
+		result[i] = f(item)
+	}
+	return result
+}
+
+// Filter returns the elements of a slice for which pred returns true.
+// A nil slice returns nil.
+// Example usage:
+// evens := Filter([]int{1, 2, 3, 4}, func(n int) bool { return n%2 == 0 })
+// fmt.Println("Evens:", evens) // [2 4]
+func Filter[T any](s []T, pred func(T) bool) []T {
+	if s == nil {
+		return nil
+	}
+
+	result := make([]T, 0, len(s))
+	for _, item := range s {
+		if pred(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Reduce folds a slice into a single value, starting from init.
+// An empty slice returns init.
+// Example usage:
+// sum := Reduce([]int{1, 2, 3}, 0, func(acc, n int) int { return acc + n })
+// fmt.Println("Sum:", sum) // 6
+func Reduce[T, U any](s []T, init U, f func(U, T) U) U {
+	acc := init
+	for _, item := range s {
+		acc = f(acc, item)
+	}
+	return acc
+}
+
+// ReverseSlice returns a new slice holding the elements of s in reverse order.
+// The input slice is left untouched, and a nil slice returns nil.
+// Example usage:
+// reversed := ReverseSlice([]int{1, 2, 3})
+// fmt.Println("Reversed:", reversed) // [3 2 1]
+func ReverseSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+
+	result := make([]T, len(s))
+	for i, item := range s {
+		result[len(s)-1-i] = item
+	}
+	return result
+}
+
+// SampleN returns n distinct elements of a slice picked at random with a PRNG
+// seeded by seed, so the same seed always gives the same sample. When n is
+// larger than the slice, all its elements are returned shuffled, and n <= 0
+// returns an empty slice. The input slice is left untouched.
+// Example usage:
+// sample := SampleN([]string{"a", "b", "c", "d"}, 2, 42)
+// fmt.Println("Sample:", sample) // the same 2 elements on every run
+func SampleN[T any](items []T, n int, seed int64) []T {
+	if n <= 0 {
+		return []T{}
+	}
+	if n > len(items) {
+		n = len(items)
+	}
+
+	shuffled := make([]T, len(items))
+	copy(shuffled, items)
+	rng := rand.New(rand.NewSource(seed))
+	// Partial Fisher-Yates shuffle: only the first n positions are drawn.
+	for i := 0; i < n; i++ {
+		j := i + rng.Intn(len(shuffled)-i)
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+	return shuffled[:n:n]
+}
+
+// PartitionInto divides a slice into n partitions of as equal size as possible.
+// When the length is not divisible by n, the remainder is spread across the
+// first partitions. No empty partition is returned, so fewer than n partitions
+// are returned when the slice has fewer than n elements, and none when n <= 0.
+// The partitions share the backing array of s.
+// Example usage:
+// parts := PartitionInto([]int{1, 2, 3, 4, 5}, 2)
+// fmt.Println("Partitions:", parts) // [[1 2 3] [4 5]]
+func PartitionInto[T any](s []T, n int) [][]T {
+	if n <= 0 || len(s) == 0 {
+		return nil
+	}
+	if n > len(s) {
+		n = len(s)
+	}
+
+	size, remainder := len(s)/n, len(s)%n
+	result := make([][]T, 0, n)
+	start := 0
+	for i := 0; i < n; i++ {
+		end := start + size
+		if i < remainder {
+			end++
+		}
+		result = append(result, s[start:end:end])
+		start = end
+	}
+	return result
+}
+
+// Chunk splits a slice into consecutive chunks of at most size elements, the
+// last chunk possibly being shorter, e.g. to batch database inserts. A size
+// <= 0 returns the whole slice as a single chunk, and an empty slice returns
+// no chunk. The chunks share the backing array of s.
+// Example usage:
+// batches := Chunk([]int{1, 2, 3, 4, 5}, 2)
+// fmt.Println("Batches:", batches) // [[1 2] [3 4] [5]]
+func Chunk[T any](s []T, size int) [][]T {
+	if len(s) == 0 {
+		return nil
+	}
+	if size <= 0 || size > len(s) {
+		size = len(s)
+	}
+
+	result := make([][]T, 0, (len(s)+size-1)/size)
+	for start := 0; start < len(s); start += size {
+		end := min(start+size, len(s))
+		result = append(result, s[start:end:end])
+	}
+	return result
+}
+
+// ParallelMap applies fn to every element of a slice using a bounded pool of
+// workers and returns the results in the same order as the input. When fn
+// returns an error, no further element is dispatched and the first error
+// encountered is returned along with a nil slice. Elements already being
+// processed by other workers are allowed to finish. A workers value <= 0 is
+// treated as 1.
+// Example usage:
+// users, err := ParallelMap(ids, 8, fetchUser)
+//
+//	if err != nil {
+//	    fmt.Println("Error fetching users:", err)
+//	}
+func ParallelMap[T, U any](items []T, workers int, fn func(T) (U, error)) ([]U, error) {
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(items) {
+		workers = len(items)
+	}
+
+	results := make([]U, len(items))
+	jobs := make(chan int)
+	done := make(chan struct{})
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result, err := fn(items[i])
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						close(done)
+					})
+					return
+				}
+				results[i] = result
+			}
+		}()
+	}
+
+dispatch:
+	for i := range items {
+		select {
+		case jobs <- i:
+		case <-done:
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
@@ -0,0 +1,194 @@
+package goease
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	lengths := Map([]string{"a", "bb", "ccc"}, func(s string) int { return len(s) })
+	if !reflect.DeepEqual(lengths, []int{1, 2, 3}) {
+		t.Errorf("unexpected result %v", lengths)
+	}
+
+	if result := Map([]string{}, func(s string) int { return len(s) }); len(result) != 0 {
+		t.Errorf("expected empty result got %v", result)
+	}
+	if result := Map(nil, func(s string) int { return len(s) }); result != nil {
+		t.Errorf("expected nil result got %v", result)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	evens := Filter([]int{1, 2, 3, 4}, func(n int) bool { return n%2 == 0 })
+	if !reflect.DeepEqual(evens, []int{2, 4}) {
+		t.Errorf("unexpected result %v", evens)
+	}
+
+	if result := Filter([]int{}, func(n int) bool { return true }); len(result) != 0 {
+		t.Errorf("expected empty result got %v", result)
+	}
+	if result := Filter(nil, func(n int) bool { return true }); result != nil {
+		t.Errorf("expected nil result got %v", result)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	sum := Reduce([]int{1, 2, 3}, 0, func(acc, n int) int { return acc + n })
+	if sum != 6 {
+		t.Errorf("expected 6 got %d", sum)
+	}
+
+	joined := Reduce([]int{1, 2}, "", func(acc string, n int) string { return acc + IntToString(n) })
+	if joined != "12" {
+		t.Errorf("expected \"12\" got %q", joined)
+	}
+
+	if result := Reduce([]int{}, 10, func(acc, n int) int { return acc + n }); result != 10 {
+		t.Errorf("expected init value 10 got %d", result)
+	}
+}
+
+func TestReverseSlice(t *testing.T) {
+	input := []int{1, 2, 3, 4}
+	if got := ReverseSlice(input); !reflect.DeepEqual(got, []int{4, 3, 2, 1}) {
+		t.Errorf("unexpected result %v", got)
+	}
+	if !reflect.DeepEqual(input, []int{1, 2, 3, 4}) {
+		t.Error("expected input to be left untouched")
+	}
+	if got := ReverseSlice([]int{}); got == nil || len(got) != 0 {
+		t.Errorf("expected an empty slice got %v", got)
+	}
+	if got := ReverseSlice[int](nil); got != nil {
+		t.Errorf("expected nil got %v", got)
+	}
+}
+
+func TestSampleN(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	sample := SampleN(items, 4, 42)
+	if len(sample) != 4 {
+		t.Fatalf("expected 4 elements got %v", sample)
+	}
+	if len(Unique(sample)) != 4 {
+		t.Errorf("expected distinct elements got %v", sample)
+	}
+	if again := SampleN(items, 4, 42); !reflect.DeepEqual(sample, again) {
+		t.Errorf("expected the same sample for the same seed got %v and %v", sample, again)
+	}
+	if !reflect.DeepEqual(items, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}) {
+		t.Error("expected input to be left untouched")
+	}
+
+	all := SampleN(items, 20, 7)
+	if len(all) != len(items) {
+		t.Fatalf("expected all elements got %v", all)
+	}
+	sorted := append([]int(nil), all...)
+	sort.Ints(sorted)
+	if !reflect.DeepEqual(sorted, items) {
+		t.Errorf("expected a permutation of the input got %v", all)
+	}
+
+	if got := SampleN(items, 0, 42); len(got) != 0 {
+		t.Errorf("expected empty sample got %v", got)
+	}
+}
+
+func TestPartitionInto(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []int
+		n        int
+		expected [][]int
+	}{
+		{"even", []int{1, 2, 3, 4, 5, 6}, 3, [][]int{{1, 2}, {3, 4}, {5, 6}}},
+		{"remainder", []int{1, 2, 3, 4, 5, 6, 7, 8}, 3, [][]int{{1, 2, 3}, {4, 5, 6}, {7, 8}}},
+		{"more partitions than elements", []int{1, 2}, 4, [][]int{{1}, {2}}},
+		{"zero partitions", []int{1, 2}, 0, nil},
+		{"empty", []int{}, 2, nil},
+	}
+	for _, tt := range tests {
+		if got := PartitionInto(tt.input, tt.n); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: expected %v got %v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestChunk(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []int
+		size     int
+		expected [][]int
+	}{
+		{"exact", []int{1, 2, 3, 4, 5, 6}, 3, [][]int{{1, 2, 3}, {4, 5, 6}}},
+		{"remainder", []int{1, 2, 3, 4, 5}, 2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{"larger than slice", []int{1, 2}, 5, [][]int{{1, 2}}},
+		{"zero size", []int{1, 2, 3}, 0, [][]int{{1, 2, 3}}},
+		{"negative size", []int{1, 2, 3}, -1, [][]int{{1, 2, 3}}},
+		{"empty", []int{}, 2, nil},
+	}
+	for _, tt := range tests {
+		if got := Chunk(tt.input, tt.size); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: expected %v got %v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestParallelMap(t *testing.T) {
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+
+	results, err := ParallelMap(items, 8, func(n int) (string, error) {
+		return IntToString(n * 2), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, result := range results {
+		if result != IntToString(i*2) {
+			t.Fatalf("expected %q at index %d got %q", IntToString(i*2), i, result)
+		}
+	}
+
+	errBoom := errors.New("boom")
+	failed, err := ParallelMap(items, 4, func(n int) (int, error) {
+		if n == 10 {
+			return 0, errBoom
+		}
+		return n, nil
+	})
+	if err != errBoom || failed != nil {
+		t.Fatalf("expected error %v and nil result got %v, %v", errBoom, err, failed)
+	}
+
+	// With a single worker nothing receives from the jobs channel once fn has
+	// failed, so no element after the failing one can ever be dispatched.
+	var calls int32
+	_, err = ParallelMap(items, 1, func(n int) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		if n == 10 {
+			return 0, errBoom
+		}
+		return n, nil
+	})
+	if err != errBoom {
+		t.Fatalf("expected error %v got %v", errBoom, err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 11 {
+		t.Errorf("expected remaining work to be cancelled after an error, got %d calls", got)
+	}
+
+	results, err = ParallelMap([]int{}, 4, func(n int) (string, error) { return "", nil })
+	if err != nil || len(results) != 0 {
+		t.Errorf("expected empty result got %v, %v", results, err)
+	}
+}